@@ -0,0 +1,276 @@
+package clienthttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker [open]")
+
+type CircuitState int
+
+const (
+	StateClosed CircuitState = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+type CircuitBreakerConfig struct {
+	FailureRate      float64
+	MinRequests      int
+	Window           time.Duration
+	Buckets          int
+	CoolDown         time.Duration
+	HalfOpenRequests int
+	IsFailure        func(resp *http.Response, err error) bool
+	OnStateChange    func(domain string, from, to CircuitState)
+}
+
+func WithCircuitBreaker(conf CircuitBreakerConfig) Option {
+	return func(c *clientHttp) {
+		c.breaker = &conf
+	}
+}
+
+func (conf CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if conf.FailureRate <= 0 || conf.FailureRate > 1 {
+		conf.FailureRate = 0.5
+	}
+
+	if conf.MinRequests < 1 {
+		conf.MinRequests = 10
+	}
+
+	if conf.Window <= 0 {
+		conf.Window = 10 * time.Second
+	}
+
+	if conf.Buckets < 1 {
+		conf.Buckets = 10
+	}
+
+	if conf.CoolDown <= 0 {
+		conf.CoolDown = 30 * time.Second
+	}
+
+	if conf.HalfOpenRequests < 1 {
+		conf.HalfOpenRequests = 1
+	}
+
+	if conf.IsFailure == nil {
+		conf.IsFailure = isFailure
+	}
+
+	return conf
+}
+
+func isFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+type bucket struct {
+	start     time.Time
+	successes int
+	failures  int
+}
+
+type rollingWindow struct {
+	buckets []bucket
+	width   time.Duration
+}
+
+func newRollingWindow(window time.Duration, size int) *rollingWindow {
+	return &rollingWindow{
+		buckets: make([]bucket, size),
+		width:   window / time.Duration(size),
+	}
+}
+
+func (w *rollingWindow) add(now time.Time, failure bool) {
+	start := now.Truncate(w.width)
+	b := &w.buckets[int(start.UnixNano()/int64(w.width))%len(w.buckets)]
+
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+
+	if failure {
+		b.failures++
+	} else {
+		b.successes++
+	}
+}
+
+func (w *rollingWindow) counts(now time.Time) (total, failures int) {
+	oldest := now.Add(-w.width * time.Duration(len(w.buckets)))
+
+	for _, b := range w.buckets {
+		if b.start.After(oldest) {
+			total += b.successes + b.failures
+			failures += b.failures
+		}
+	}
+
+	return total, failures
+}
+
+func (w *rollingWindow) reset() {
+	for i := range w.buckets {
+		w.buckets[i] = bucket{}
+	}
+}
+
+type circuitBreaker struct {
+	next   ClientAPI
+	domain string
+	conf   CircuitBreakerConfig
+	now    func() time.Time
+
+	mu                sync.Mutex
+	state             CircuitState
+	openedAt          time.Time
+	halfOpenInFlight  int
+	halfOpenSuccesses int
+	window            *rollingWindow
+	changes           []func()
+}
+
+func newCircuitBreaker(next ClientAPI, domain string, conf CircuitBreakerConfig) *circuitBreaker {
+	conf = conf.withDefaults()
+
+	return &circuitBreaker{
+		next:   next,
+		domain: domain,
+		conf:   conf,
+		now:    time.Now,
+		window: newRollingWindow(conf.Window, conf.Buckets),
+	}
+}
+
+func (cb *circuitBreaker) Do(req *http.Request) (*http.Response, error) {
+	state, err := cb.allow()
+	if err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+
+	resp, err := cb.next.Do(req)
+
+	cb.record(state, cb.conf.IsFailure(resp, err))
+
+	return resp, err
+}
+
+func (cb *circuitBreaker) allow() (CircuitState, error) {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	if cb.state == StateOpen {
+		if cb.now().Sub(cb.openedAt) < cb.conf.CoolDown {
+			return cb.state, ErrCircuitOpen
+		}
+
+		cb.transition(StateHalfOpen)
+	}
+
+	if cb.state == StateHalfOpen {
+		if cb.halfOpenInFlight >= cb.conf.HalfOpenRequests {
+			return cb.state, ErrCircuitOpen
+		}
+
+		cb.halfOpenInFlight++
+	}
+
+	return cb.state, nil
+}
+
+func (cb *circuitBreaker) record(state CircuitState, failure bool) {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	if state == StateHalfOpen {
+		if cb.state != StateHalfOpen {
+			return
+		}
+
+		cb.halfOpenInFlight--
+
+		if failure {
+			cb.transition(StateOpen)
+			return
+		}
+
+		cb.halfOpenSuccesses++
+		if cb.halfOpenSuccesses >= cb.conf.HalfOpenRequests {
+			cb.transition(StateClosed)
+		}
+
+		return
+	}
+
+	if cb.state != StateClosed {
+		return
+	}
+
+	now := cb.now()
+	cb.window.add(now, failure)
+
+	total, failures := cb.window.counts(now)
+	if total >= cb.conf.MinRequests && float64(failures)/float64(total) >= cb.conf.FailureRate {
+		cb.transition(StateOpen)
+	}
+}
+
+func (cb *circuitBreaker) transition(to CircuitState) {
+	from := cb.state
+	if from == to {
+		return
+	}
+
+	cb.state = to
+	cb.halfOpenInFlight = 0
+	cb.halfOpenSuccesses = 0
+
+	switch to {
+	case StateOpen:
+		cb.openedAt = cb.now()
+	case StateClosed:
+		cb.window.reset()
+	}
+
+	if cb.conf.OnStateChange != nil {
+		cb.changes = append(cb.changes, func() {
+			cb.conf.OnStateChange(cb.domain, from, to)
+		})
+	}
+}
+
+func (cb *circuitBreaker) unlock() {
+	changes := cb.changes
+	cb.changes = nil
+	cb.mu.Unlock()
+
+	for _, notify := range changes {
+		notify()
+	}
+}
@@ -0,0 +1,113 @@
+package clienthttp_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientHttp_CircuitBreaker(t *testing.T) {
+	t.Run("should open the circuit and stop calling the upstream [OPEN]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithCircuitBreaker(clienthttp.CircuitBreakerConfig{
+			FailureRate: 0.5,
+			MinRequests: 3,
+			CoolDown:    time.Minute,
+		}))
+
+		for i := 0; i < 3; i++ {
+			_, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test/breaker").Build())
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusInternalServerError, statusCode)
+		}
+
+		_, _, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test/breaker").Build())
+
+		assert.ErrorIs(t, err, clienthttp.ErrCircuitOpen)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should close the circuit after a successful trial request [HALF OPEN]", func(t *testing.T) {
+		var (
+			failing int32 = 1
+			mu      sync.Mutex
+			changes []string
+		)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.LoadInt32(&failing) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+
+			fmt.Fprintf(w, "ok")
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithCircuitBreaker(clienthttp.CircuitBreakerConfig{
+			MinRequests: 1,
+			CoolDown:    50 * time.Millisecond,
+			OnStateChange: func(domain string, from, to clienthttp.CircuitState) {
+				assert.Equal(t, ts.URL, domain)
+
+				mu.Lock()
+				changes = append(changes, fmt.Sprintf("%s->%s", from, to))
+				mu.Unlock()
+			},
+		}))
+
+		_, _, _ = client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test/breaker").Build())
+
+		_, _, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test/breaker").Build())
+		assert.ErrorIs(t, err, clienthttp.ErrCircuitOpen)
+
+		atomic.StoreInt32(&failing, 0)
+		time.Sleep(60 * time.Millisecond)
+
+		_, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test/breaker").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, changes)
+	})
+
+	t.Run("should not retry when the circuit is open [RETRY]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL,
+			clienthttp.WithCircuitBreaker(clienthttp.CircuitBreakerConfig{MinRequests: 2, CoolDown: time.Minute}),
+			clienthttp.WithRetryPolicy(clienthttp.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond}),
+		)
+
+		_, _, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test/breaker").Build())
+
+		assert.ErrorIs(t, err, clienthttp.ErrCircuitOpen)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+}
@@ -0,0 +1,511 @@
+package clienthttp
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/redis"
+)
+
+const revalidateTimeout = 30 * time.Second
+
+type CachedResponse struct {
+	StatusCode int               `json:"status_code"`
+	Header     http.Header       `json:"header"`
+	Body       []byte            `json:"body"`
+	StoredAt   time.Time         `json:"stored_at"`
+	Vary       map[string]string `json:"vary,omitempty"`
+}
+
+type CacheStore interface {
+	Get(ctx context.Context, key string) (*CachedResponse, bool, error)
+	Set(ctx context.Context, key string, entry *CachedResponse, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+type CacheConfig struct {
+	Store                CacheStore
+	StaleWhileRevalidate time.Duration
+	StaleTTL             time.Duration
+	// Shared marks a custom Store as shared between users, like the redis
+	// store always is, so private and authorized responses aren't stored.
+	Shared bool
+}
+
+func WithCache(conf CacheConfig) Option {
+	return func(c *clientHttp) {
+		c.cache = &conf
+	}
+}
+
+type cacheClient struct {
+	next ClientAPI
+	conf CacheConfig
+	now  func() time.Time
+
+	mu           sync.Mutex
+	revalidating map[string]bool
+}
+
+func newCacheClient(next ClientAPI, conf CacheConfig) *cacheClient {
+	if conf.Store == nil {
+		conf.Store = NewLRUCacheStore(1000)
+	}
+
+	if conf.StaleTTL <= 0 {
+		conf.StaleTTL = time.Hour
+	}
+
+	return &cacheClient{
+		next:         next,
+		conf:         conf,
+		now:          time.Now,
+		revalidating: make(map[string]bool),
+	}
+}
+
+func (c *cacheClient) Do(req *http.Request) (*http.Response, error) {
+	requestDirectives := cacheDirectives(req.Header)
+	if req.Method != http.MethodGet || requestDirectives.has("no-store") {
+		return c.next.Do(req)
+	}
+
+	key := req.Method + " " + req.URL.String()
+
+	entry, ok, err := c.conf.Store.Get(req.Context(), key)
+	if err != nil || !ok || !entry.matches(req) {
+		return c.fetch(req, key, nil)
+	}
+
+	age := c.now().Sub(entry.StoredAt)
+
+	if !requestDirectives.has("no-cache") {
+		freshness := entry.freshness()
+
+		if age < freshness {
+			return entry.response(req, age), nil
+		}
+
+		if age < freshness+c.staleWindow(entry) {
+			c.revalidate(req, key, entry.clone())
+			return entry.response(req, age), nil
+		}
+	}
+
+	return c.fetch(req, key, entry)
+}
+
+func (c *cacheClient) fetch(req *http.Request, key string, entry *CachedResponse) (*http.Response, error) {
+	outReq := req
+
+	if entry != nil && entry.hasValidators() {
+		outReq = req.Clone(req.Context())
+
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			outReq.Header.Set("If-None-Match", etag)
+		}
+
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			outReq.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := c.next.Do(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		discardResponse(resp)
+
+		for key, values := range resp.Header {
+			entry.Header[key] = values
+		}
+
+		entry.StoredAt = c.now()
+		c.store(req.Context(), key, entry)
+
+		return entry.response(req, 0), nil
+	}
+
+	entry = &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		StoredAt:   c.now(),
+		Vary:       varyValues(resp.Header, req),
+	}
+
+	if !isCacheable(req, resp, c.shared()) ||
+		(entry.freshness() <= 0 && c.staleWindow(entry) <= 0 && !entry.hasValidators()) {
+		return resp, nil
+	}
+
+	ctx := req.Context()
+	resp.Body = &cachingBody{ReadCloser: resp.Body, store: func(body []byte) {
+		entry.Body = body
+		c.store(ctx, key, entry)
+	}}
+
+	return resp, nil
+}
+
+func (c *cacheClient) revalidate(req *http.Request, key string, entry *CachedResponse) {
+	c.mu.Lock()
+	if c.revalidating[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.revalidating[key] = true
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.revalidating, key)
+			c.mu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), revalidateTimeout)
+		defer cancel()
+
+		background, err := replayRequest(req.WithContext(ctx))
+		if err != nil {
+			return
+		}
+
+		resp, err := c.fetch(background, key, entry)
+		if err == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+	}()
+}
+
+func (c *cacheClient) store(ctx context.Context, key string, entry *CachedResponse) {
+	ttl := entry.freshness() + c.staleWindow(entry)
+	if entry.hasValidators() {
+		ttl += c.conf.StaleTTL
+	}
+
+	_ = c.conf.Store.Set(ctx, key, entry, ttl)
+}
+
+func (c *cacheClient) staleWindow(entry *CachedResponse) time.Duration {
+	if seconds, ok := cacheDirectives(entry.Header).seconds("stale-while-revalidate"); ok {
+		return seconds
+	}
+
+	return c.conf.StaleWhileRevalidate
+}
+
+// shared tells whether the store is shared between clients, where responses
+// to authenticated or private requests must not be stored (RFC 9111 §3.5).
+func (c *cacheClient) shared() bool {
+	_, ok := c.conf.Store.(*redisCacheStore)
+	return ok || c.conf.Shared
+}
+
+func isCacheable(req *http.Request, resp *http.Response, shared bool) bool {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNonAuthoritativeInfo, http.StatusMultipleChoices,
+		http.StatusMovedPermanently, http.StatusNotFound, http.StatusGone:
+	default:
+		return false
+	}
+
+	directives := cacheDirectives(resp.Header)
+	if directives.has("no-store") || resp.Header.Get("Vary") == "*" {
+		return false
+	}
+
+	if !shared {
+		return true
+	}
+
+	if directives.has("private") {
+		return false
+	}
+
+	// Authorization may also be added further down the chain, by the auth
+	// middleware or a signer, so the request actually sent is checked too.
+	authorized := req.Header.Get("Authorization") != "" ||
+		(resp.Request != nil && resp.Request.Header.Get("Authorization") != "")
+
+	return !authorized || directives.has("public") || directives.has("s-maxage") || directives.has("must-revalidate")
+}
+
+// cachingBody hands the body to the caller as it arrives, so streaming and
+// size limits keep working, and stores it once read to the end.
+type cachingBody struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	store func(body []byte)
+}
+
+func (b *cachingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+
+	if err == io.EOF && b.store != nil {
+		b.store(b.buf.Bytes())
+		b.store = nil
+	}
+
+	return n, err
+}
+
+func varyValues(header http.Header, req *http.Request) map[string]string {
+	var values map[string]string
+
+	for _, vary := range header.Values("Vary") {
+		for _, name := range strings.Split(vary, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			if values == nil {
+				values = make(map[string]string)
+			}
+
+			values[name] = strings.Join(req.Header.Values(name), ",")
+		}
+	}
+
+	return values
+}
+
+func (e *CachedResponse) matches(req *http.Request) bool {
+	for name, value := range e.Vary {
+		if strings.Join(req.Header.Values(name), ",") != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e *CachedResponse) freshness() time.Duration {
+	directives := cacheDirectives(e.Header)
+	if directives.has("no-cache") {
+		return 0
+	}
+
+	if maxAge, ok := directives.seconds("max-age"); ok {
+		return maxAge
+	}
+
+	expires, err := http.ParseTime(e.Header.Get("Expires"))
+	if err != nil {
+		return 0
+	}
+
+	date, err := http.ParseTime(e.Header.Get("Date"))
+	if err != nil {
+		date = e.StoredAt
+	}
+
+	return expires.Sub(date)
+}
+
+func (e *CachedResponse) hasValidators() bool {
+	return e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != ""
+}
+
+func (e *CachedResponse) response(req *http.Request, age time.Duration) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.Itoa(int(age.Seconds())))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func (e *CachedResponse) clone() *CachedResponse {
+	clone := *e
+	clone.Header = e.Header.Clone()
+
+	return &clone
+}
+
+type directives map[string]string
+
+func cacheDirectives(header http.Header) directives {
+	result := directives{}
+
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name != "" {
+				result[strings.ToLower(name)] = strings.Trim(arg, `"`)
+			}
+		}
+	}
+
+	return result
+}
+
+func (d directives) has(name string) bool {
+	_, ok := d[name]
+	return ok
+}
+
+func (d directives) seconds(name string) (time.Duration, bool) {
+	value, ok := d[name]
+	if !ok {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+type lruCacheStore struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type lruItem struct {
+	key       string
+	entry     *CachedResponse
+	expiresAt time.Time
+}
+
+func NewLRUCacheStore(capacity int) CacheStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &lruCacheStore{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (s *lruCacheStore) Get(_ context.Context, key string) (*CachedResponse, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	item := element.Value.(*lruItem)
+	if s.now().After(item.expiresAt) {
+		s.order.Remove(element)
+		delete(s.items, key)
+
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(element)
+
+	return item.entry.clone(), true, nil
+}
+
+func (s *lruCacheStore) Set(_ context.Context, key string, entry *CachedResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := &lruItem{key: key, entry: entry.clone(), expiresAt: s.now().Add(ttl)}
+
+	if element, ok := s.items[key]; ok {
+		element.Value = item
+		s.order.MoveToFront(element)
+
+		return nil
+	}
+
+	s.items[key] = s.order.PushFront(item)
+
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*lruItem).key)
+	}
+
+	return nil
+}
+
+func (s *lruCacheStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.items[key]; ok {
+		s.order.Remove(element)
+		delete(s.items, key)
+	}
+
+	return nil
+}
+
+type redisCacheStore struct {
+	client redis.ClientRedis
+	prefix string
+}
+
+func NewRedisCacheStore(client redis.ClientRedis, prefix string) CacheStore {
+	return &redisCacheStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *redisCacheStore) Get(ctx context.Context, key string) (*CachedResponse, bool, error) {
+	value, err := s.client.Get(ctx, s.prefix+key)
+	if errors.Is(err, redis.ErrNil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var entry CachedResponse
+	if err = json.Unmarshal([]byte(value), &entry); err != nil {
+		return nil, false, err
+	}
+
+	return &entry, true, nil
+}
+
+func (s *redisCacheStore) Set(ctx context.Context, key string, entry *CachedResponse, ttl time.Duration) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Set(ctx, s.prefix+key, string(value), ttl)
+}
+
+func (s *redisCacheStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key)
+}
@@ -0,0 +1,357 @@
+package clienthttp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+}
+
+type cacheFixture struct {
+	client ClientHTTP
+	clock  *fakeClock
+	calls  *int32
+}
+
+func setupCacheFixture(t *testing.T, conf CacheConfig, handler func(w http.ResponseWriter, r *http.Request, call int32)) *cacheFixture {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, atomic.AddInt32(&calls, 1))
+	}))
+	t.Cleanup(ts.Close)
+
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+
+	cache := newCacheClient(http.DefaultClient, conf)
+	cache.now = clock.Now
+
+	return &cacheFixture{
+		client: NewClientHTTP(cache, ts.URL),
+		clock:  clock,
+		calls:  &calls,
+	}
+}
+
+func (f *cacheFixture) get(t *testing.T, headers ...string) string {
+	builder := NewRequest(http.MethodGet, "/reference-data")
+	for i := 0; i < len(headers); i += 2 {
+		builder = builder.WithHeader(headers[i], headers[i+1])
+	}
+
+	body, statusCode, err := f.client.Do(context.TODO(), builder.Build())
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	return string(body)
+}
+
+func TestCacheClient(t *testing.T) {
+	t.Run("should serve fresh responses from the cache [MAX AGE]", func(t *testing.T) {
+		f := setupCacheFixture(t, CacheConfig{}, func(w http.ResponseWriter, r *http.Request, call int32) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			fmt.Fprintf(w, "response %d", call)
+		})
+
+		assert.Equal(t, "response 1", f.get(t))
+
+		f.clock.Advance(30 * time.Second)
+		assert.Equal(t, "response 1", f.get(t))
+
+		f.clock.Advance(31 * time.Second)
+		assert.Equal(t, "response 2", f.get(t))
+		assert.Equal(t, int32(2), atomic.LoadInt32(f.calls))
+	})
+
+	t.Run("should revalidate stale entries with ETag and Last-Modified [304]", func(t *testing.T) {
+		f := setupCacheFixture(t, CacheConfig{}, func(w http.ResponseWriter, r *http.Request, call int32) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				assert.Equal(t, "Tue, 14 Nov 2023 22:13:20 GMT", r.Header.Get("If-Modified-Since"))
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Last-Modified", "Tue, 14 Nov 2023 22:13:20 GMT")
+			fmt.Fprintf(w, "response %d", call)
+		})
+
+		assert.Equal(t, "response 1", f.get(t))
+		assert.Equal(t, "response 1", f.get(t))
+		assert.Equal(t, int32(2), atomic.LoadInt32(f.calls))
+	})
+
+	t.Run("should keep variants apart using Vary [VARY]", func(t *testing.T) {
+		f := setupCacheFixture(t, CacheConfig{}, func(w http.ResponseWriter, r *http.Request, call int32) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "Accept-Language")
+			fmt.Fprintf(w, "%s %d", r.Header.Get("Accept-Language"), call)
+		})
+
+		assert.Equal(t, "es 1", f.get(t, "Accept-Language", "es"))
+		assert.Equal(t, "es 1", f.get(t, "Accept-Language", "es"))
+		assert.Equal(t, "en 2", f.get(t, "Accept-Language", "en"))
+	})
+
+	t.Run("should not store responses with no-store [NO STORE]", func(t *testing.T) {
+		f := setupCacheFixture(t, CacheConfig{}, func(w http.ResponseWriter, r *http.Request, call int32) {
+			w.Header().Set("Cache-Control", "no-store, max-age=60")
+			fmt.Fprintf(w, "response %d", call)
+		})
+
+		assert.Equal(t, "response 1", f.get(t))
+		assert.Equal(t, "response 2", f.get(t))
+	})
+
+	t.Run("should bypass the cache when the request asks for no-cache [REQUEST]", func(t *testing.T) {
+		f := setupCacheFixture(t, CacheConfig{}, func(w http.ResponseWriter, r *http.Request, call int32) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			fmt.Fprintf(w, "response %d", call)
+		})
+
+		assert.Equal(t, "response 1", f.get(t))
+		assert.Equal(t, "response 2", f.get(t, "Cache-Control", "no-cache"))
+		assert.Equal(t, "response 2", f.get(t))
+	})
+
+	t.Run("should serve stale content while revalidating in background [SWR]", func(t *testing.T) {
+		f := setupCacheFixture(t, CacheConfig{}, func(w http.ResponseWriter, r *http.Request, call int32) {
+			w.Header().Set("Cache-Control", "max-age=10, stale-while-revalidate=60")
+			fmt.Fprintf(w, "response %d", call)
+		})
+
+		assert.Equal(t, "response 1", f.get(t))
+
+		f.clock.Advance(20 * time.Second)
+		assert.Equal(t, "response 1", f.get(t))
+
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(f.calls) == 2
+		}, time.Second, 5*time.Millisecond)
+
+		assert.Eventually(t, func() bool {
+			return f.get(t) == "response 2"
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("should stream responses and honour the max body size [STREAM]", func(t *testing.T) {
+		release := make(chan struct{})
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("Cache-Control", "max-age=60")
+
+			if r.URL.Path == "/large" {
+				fmt.Fprint(w, "a response larger than the limit")
+				return
+			}
+
+			fmt.Fprintln(w, "first")
+			w.(http.Flusher).Flush()
+			<-release
+			fmt.Fprintln(w, "second")
+		}))
+		t.Cleanup(ts.Close)
+
+		client := NewClientHTTP(newCacheClient(http.DefaultClient, CacheConfig{}), ts.URL, WithMaxBodySize(10))
+
+		stream, err := client.(StreamClient).DoStream(context.TODO(), NewRequest(http.MethodGet, "/events").Build())
+		assert.NoError(t, err)
+
+		line, err := bufio.NewReader(stream.Body).ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "first\n", line)
+		close(release)
+		_ = stream.Body.Close()
+
+		for i := 0; i < 2; i++ {
+			_, _, err = client.Do(context.TODO(), NewRequest(http.MethodGet, "/large").Build())
+
+			var tooLarge *BodyTooLargeError
+			assert.ErrorAs(t, err, &tooLarge)
+		}
+
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should keep private and authorized responses out of shared stores [SHARED]", func(t *testing.T) {
+		f := setupCacheFixture(t, CacheConfig{Store: NewRedisCacheStore(newFakeRedis(), "cache:")}, func(w http.ResponseWriter, r *http.Request, call int32) {
+			switch {
+			case r.Header.Get("Accept") == "private":
+				w.Header().Set("Cache-Control", "private, max-age=60")
+			case r.Header.Get("Accept") == "public":
+				w.Header().Set("Cache-Control", "public, max-age=60")
+			default:
+				w.Header().Set("Cache-Control", "max-age=60")
+			}
+
+			fmt.Fprintf(w, "response %d", call)
+		})
+
+		assert.Equal(t, "response 1", f.get(t, "Accept", "private"))
+		assert.Equal(t, "response 2", f.get(t, "Accept", "private"))
+
+		assert.Equal(t, "response 3", f.get(t, "Authorization", "Bearer token"))
+		assert.Equal(t, "response 4", f.get(t, "Authorization", "Bearer token"))
+
+		assert.Equal(t, "response 5", f.get(t, "Authorization", "Bearer token", "Accept", "public"))
+		assert.Equal(t, "response 5", f.get(t, "Authorization", "Bearer token", "Accept", "public"))
+	})
+}
+
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+	evals  map[string]int
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: make(map[string]string), evals: make(map[string]int)}
+}
+
+func (f *fakeRedis) Get(_ context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok := f.values[key]
+	if !ok {
+		return "", redis.ErrNil
+	}
+
+	return value, nil
+}
+
+func (f *fakeRedis) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.values[key] = fmt.Sprint(value)
+
+	return nil
+}
+
+func (f *fakeRedis) Del(_ context.Context, keys ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, key := range keys {
+		delete(f.values, key)
+	}
+
+	return nil
+}
+
+func (f *fakeRedis) MSet(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	for key, value := range values {
+		_ = f.Set(ctx, key, value, ttl)
+	}
+
+	return nil
+}
+
+func (f *fakeRedis) MGet(ctx context.Context, keys ...string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+	for _, key := range keys {
+		result[key], _ = f.Get(ctx, key)
+	}
+
+	return result, nil
+}
+
+// Eval emulates the token bucket script without refill: a key admits burst
+// calls and rejects the rest.
+func (f *fakeRedis) Eval(_ context.Context, _ string, keys []string, args ...interface{}) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.evals[keys[0]] >= int(args[1].(float64)) {
+		return []interface{}{int64(0), int64(1000)}, nil
+	}
+
+	f.evals[keys[0]]++
+
+	return []interface{}{int64(1), int64(0)}, nil
+}
+
+func TestRedisCacheStore(t *testing.T) {
+	t.Run("should round trip entries through redis", func(t *testing.T) {
+		ctx := context.Background()
+		store := NewRedisCacheStore(newFakeRedis(), "http-cache:")
+
+		_, ok, err := store.Get(ctx, "GET /users")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		entry := &CachedResponse{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Etag": {`"v1"`}},
+			Body:       []byte(`{"id":1}`),
+			StoredAt:   time.Unix(1700000000, 0).UTC(),
+		}
+
+		assert.NoError(t, store.Set(ctx, "GET /users", entry, time.Minute))
+
+		cached, ok, err := store.Get(ctx, "GET /users")
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, entry, cached)
+
+		assert.NoError(t, store.Delete(ctx, "GET /users"))
+
+		_, ok, _ = store.Get(ctx, "GET /users")
+		assert.False(t, ok)
+	})
+}
+
+func TestLRUCacheStore(t *testing.T) {
+	t.Run("should evict the least recently used entry", func(t *testing.T) {
+		ctx := context.Background()
+		store := NewLRUCacheStore(2)
+
+		for _, key := range []string{"a", "b"} {
+			_ = store.Set(ctx, key, &CachedResponse{Header: http.Header{}}, time.Minute)
+		}
+
+		_, _, _ = store.Get(ctx, "a")
+		_ = store.Set(ctx, "c", &CachedResponse{Header: http.Header{}}, time.Minute)
+
+		_, okA, _ := store.Get(ctx, "a")
+		_, okB, _ := store.Get(ctx, "b")
+		_, okC, _ := store.Get(ctx, "c")
+
+		assert.True(t, okA)
+		assert.False(t, okB)
+		assert.True(t, okC)
+	})
+}
@@ -0,0 +1,314 @@
+package clienthttp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	scrubbedValue = "[scrubbed]"
+	// base64Body marks bodies that aren't valid UTF-8, such as images or
+	// protobuf payloads, which are kept base64 encoded in the cassette.
+	base64Body = "base64"
+)
+
+// DefaultScrubbedHeaders never reach a cassette with their real value.
+var DefaultScrubbedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Signature",
+	"X-Amz-Security-Token",
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method" yaml:"method"`
+	URL    string      `json:"url" yaml:"url"`
+	Header http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body   string      `json:"body,omitempty" yaml:"body,omitempty"`
+	// BodyEncoding is "base64" for binary bodies and empty for text.
+	BodyEncoding string `json:"body_encoding,omitempty" yaml:"body_encoding,omitempty"`
+}
+
+func (r RecordedRequest) BodyBytes() ([]byte, error) {
+	return decodeRecordedBody(r.Body, r.BodyEncoding)
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code" yaml:"status_code"`
+	Header     http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body       string      `json:"body,omitempty" yaml:"body,omitempty"`
+	// BodyEncoding is "base64" for binary bodies and empty for text.
+	BodyEncoding string `json:"body_encoding,omitempty" yaml:"body_encoding,omitempty"`
+}
+
+func (r RecordedResponse) BodyBytes() ([]byte, error) {
+	return decodeRecordedBody(r.Body, r.BodyEncoding)
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request" yaml:"request"`
+	Response RecordedResponse `json:"response" yaml:"response"`
+}
+
+// Cassette is a list of recorded interactions persisted as YAML when the
+// file extension is .yaml or .yml, and as JSON otherwise.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions" yaml:"interactions"`
+
+	path string
+	mu   sync.Mutex
+}
+
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cassette := &Cassette{path: path}
+	if isYAML(path) {
+		err = yaml.Unmarshal(data, cassette)
+	} else {
+		err = json.Unmarshal(data, cassette)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cassette, nil
+}
+
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var (
+		data []byte
+		err  error
+	)
+
+	if isYAML(c.path) {
+		data, err = yaml.Marshal(c)
+	} else {
+		data, err = json.MarshalIndent(c, "", "  ")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, data, 0o644)
+}
+
+func (c *Cassette) add(interaction Interaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Interactions = append(c.Interactions, interaction)
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// CassetteMatcher decides whether a recorded request answers req during replay.
+type CassetteMatcher func(recorded RecordedRequest, req *http.Request) bool
+
+type CassetteConfig struct {
+	// ScrubHeaders replaces DefaultScrubbedHeaders when set.
+	ScrubHeaders []string
+	// Matchers default to MatchMethod, MatchURL and MatchBody.
+	Matchers []CassetteMatcher
+}
+
+func (c CassetteConfig) scrubHeaders() []string {
+	if c.ScrubHeaders == nil {
+		return DefaultScrubbedHeaders
+	}
+
+	return c.ScrubHeaders
+}
+
+func (c CassetteConfig) matchers() []CassetteMatcher {
+	if c.Matchers == nil {
+		return []CassetteMatcher{MatchMethod, MatchURL, MatchBody}
+	}
+
+	return c.Matchers
+}
+
+func MatchMethod(recorded RecordedRequest, req *http.Request) bool {
+	return recorded.Method == req.Method
+}
+
+// MatchURL compares path and query, ignoring the host so cassettes recorded
+// against one environment replay against any domain.
+func MatchURL(recorded RecordedRequest, req *http.Request) bool {
+	return MatchPath(recorded, req) && MatchQuery(recorded, req)
+}
+
+func MatchPath(recorded RecordedRequest, req *http.Request) bool {
+	u, err := req.URL.Parse(recorded.URL)
+	return err == nil && u.Path == req.URL.Path
+}
+
+func MatchQuery(recorded RecordedRequest, req *http.Request) bool {
+	u, err := req.URL.Parse(recorded.URL)
+	return err == nil && u.Query().Encode() == req.URL.Query().Encode()
+}
+
+func MatchBody(recorded RecordedRequest, req *http.Request) bool {
+	expected, err := recorded.BodyBytes()
+	if err != nil {
+		return false
+	}
+
+	body, err := peekBody(req)
+	return err == nil && bytes.Equal(expected, body)
+}
+
+// MatchHeaders compares only the given headers; scrubbed headers should not be
+// listed as their recorded value is a placeholder.
+func MatchHeaders(keys ...string) CassetteMatcher {
+	return func(recorded RecordedRequest, req *http.Request) bool {
+		for _, key := range keys {
+			if recorded.Header.Get(key) != req.Header.Get(key) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Recorder is a ClientAPI that sends requests through a real transport and
+// appends every exchange to a cassette, written to disk by Save.
+type Recorder struct {
+	next     ClientAPI
+	cassette *Cassette
+	scrub    []string
+}
+
+func NewRecorder(next ClientAPI, path string, conf CassetteConfig) *Recorder {
+	return &Recorder{
+		next:     next,
+		cassette: &Cassette{path: path},
+		scrub:    conf.scrubHeaders(),
+	}
+}
+
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	reqBody, err := peekBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	recordedRequest := RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: scrub(req.Header, r.scrub),
+	}
+	recordedRequest.Body, recordedRequest.BodyEncoding = encodeRecordedBody(reqBody)
+
+	recordedResponse := RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     scrub(resp.Header, r.scrub),
+	}
+	recordedResponse.Body, recordedResponse.BodyEncoding = encodeRecordedBody(respBody)
+
+	r.cassette.add(Interaction{Request: recordedRequest, Response: recordedResponse})
+
+	return resp, nil
+}
+
+func (r *Recorder) Cassette() *Cassette {
+	return r.cassette
+}
+
+func (r *Recorder) Save() error {
+	return r.cassette.Save()
+}
+
+func scrub(header http.Header, keys []string) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	scrubbed := header.Clone()
+	for _, key := range keys {
+		if _, ok := scrubbed[http.CanonicalHeaderKey(key)]; ok {
+			scrubbed.Set(key, scrubbedValue)
+		}
+	}
+
+	return scrubbed
+}
+
+// encodeRecordedBody keeps text bodies readable in the cassette.
+func encodeRecordedBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+
+	return base64.StdEncoding.EncodeToString(body), base64Body
+}
+
+func decodeRecordedBody(body, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case base64Body:
+		return base64.StdEncoding.DecodeString(body)
+	}
+
+	return nil, fmt.Errorf("cassette [ unknown body encoding %q ]", encoding)
+}
+
+// peekBody reads the request body and leaves an identical one in its place.
+func peekBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
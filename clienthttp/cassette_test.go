@@ -0,0 +1,122 @@
+package clienthttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func recordCassette(t *testing.T, path string) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"path":"%s","page":"%s"}`, r.URL.Path, r.URL.Query().Get("page"))
+	}))
+	defer ts.Close()
+
+	recorder := NewRecorder(http.DefaultClient, path, CassetteConfig{})
+	client := NewClientHTTP(recorder, ts.URL)
+
+	for _, page := range []string{"1", "2"} {
+		_, _, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").
+			WithHeader("Authorization", "Bearer secret-token").
+			WithQueryParam("page", page).
+			Build())
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, recorder.Save())
+}
+
+func TestCassette(t *testing.T) {
+	t.Run("should record interactions and scrub secret headers [RECORD]", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "users.yaml")
+		recordCassette(t, path)
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(data), "secret")
+
+		cassette, err := LoadCassette(path)
+
+		assert.NoError(t, err)
+		assert.Len(t, cassette.Interactions, 2)
+		assert.Equal(t, scrubbedValue, cassette.Interactions[0].Request.Header.Get("Authorization"))
+		assert.Equal(t, scrubbedValue, cassette.Interactions[0].Response.Header.Get("Set-Cookie"))
+		assert.Equal(t, `{"path":"/users","page":"2"}`, cassette.Interactions[1].Response.Body)
+	})
+
+	t.Run("should replay a cassette through the mock client [REPLAY]", func(t *testing.T) {
+		for _, name := range []string{"users.yaml", "users.json"} {
+			path := filepath.Join(t.TempDir(), name)
+			recordCassette(t, path)
+
+			mockClient := NewMockClient(t)
+			assert.NoError(t, mockClient.ReplayCassette(path, CassetteConfig{}))
+
+			body, statusCode, err := mockClient.Do(context.TODO(), NewRequest(http.MethodGet, "/users").WithQueryParam("page", "2").Build())
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.Equal(t, `{"path":"/users","page":"2"}`, string(body))
+
+			_, _, err = mockClient.Do(context.TODO(), NewRequest(http.MethodGet, "/users").WithQueryParam("page", "1").Build())
+
+			assert.NoError(t, err)
+			mockClient.AssertExpectations()
+		}
+	})
+
+	t.Run("should honour the configured matchers [MATCHERS]", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "users.json")
+		recordCassette(t, path)
+
+		mockClient := NewMockClient(t)
+		assert.NoError(t, mockClient.ReplayCassette(path, CassetteConfig{Matchers: []CassetteMatcher{MatchMethod, MatchPath}}))
+
+		body, _, err := mockClient.Do(context.TODO(), NewRequest(http.MethodGet, "/users").WithQueryParam("page", "9").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{"path":"/users","page":"1"}`, string(body))
+	})
+	t.Run("should keep binary bodies intact [BINARY]", func(t *testing.T) {
+		image := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, 0xfe, '\n'}
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(image)
+		}))
+		defer ts.Close()
+
+		for _, name := range []string{"avatar.yaml", "avatar.json"} {
+			path := filepath.Join(t.TempDir(), name)
+
+			recorder := NewRecorder(http.DefaultClient, path, CassetteConfig{})
+			_, _, err := NewClientHTTP(recorder, ts.URL).Do(context.TODO(), NewRequest(http.MethodPut, "/avatar").
+				WithHeader("X-Amz-Security-Token", "secret-token").
+				WithBodyBytes(image).
+				Build())
+			assert.NoError(t, err)
+			assert.NoError(t, recorder.Save())
+
+			cassette, err := LoadCassette(path)
+			assert.NoError(t, err)
+			assert.Equal(t, base64Body, cassette.Interactions[0].Response.BodyEncoding)
+			assert.Equal(t, scrubbedValue, cassette.Interactions[0].Request.Header.Get("X-Amz-Security-Token"))
+
+			mockClient := NewMockClient(t)
+			assert.NoError(t, mockClient.ReplayCassette(path, CassetteConfig{}))
+
+			body, _, err := mockClient.Do(context.TODO(), NewRequest(http.MethodPut, "/avatar").WithBodyBytes(image).Build())
+
+			assert.NoError(t, err)
+			assert.Equal(t, image, body)
+		}
+	})
+}
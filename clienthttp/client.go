@@ -2,11 +2,12 @@ package clienthttp
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/dot-backend/synergetic-craft/logger"
+	"github.com/dot-backend/synergetic-craft/telemetry"
 )
 
 type ClientAPI interface {
@@ -18,69 +19,192 @@ type ClientHTTP interface {
 	DoWithTimeout(ctx context.Context, req *http.Request, timeout int64, expectedCode int, out interface{}) error
 }
 
+// HandlerClient sends a request and lets a ResponseHandler decode the response.
+type HandlerClient interface {
+	Handle(ctx context.Context, req *http.Request, handler ResponseHandler) (*HandledResponse, error)
+}
+
+// StreamClient sends a request and returns the response body unread.
+type StreamClient interface {
+	DoStream(ctx context.Context, req *http.Request) (*StreamResponse, error)
+}
+
+type Option func(*clientHttp)
+
 type clientHttp struct {
-	domain string
-	client ClientAPI
+	domain      string
+	client      ClientAPI
+	transport   ClientAPI
+	retry       *RetryPolicy
+	breaker     *CircuitBreakerConfig
+	maxBodySize int64
+	middlewares []Middleware
+	auth        TokenSource
+	signer      Signer
+	cache       *CacheConfig
+	rateLimit   *RateLimitConfig
+	hedge       *HedgeConfig
+	idempotency *IdempotencyConfig
+	telemetry   *telemetry.Config
+	log         logger.Logger
+
+	timeout        time.Duration
+	attemptTimeout time.Duration
+}
+
+func NewClientHTTP(clientAPI ClientAPI, domain string, opts ...Option) ClientHTTP {
+	return newClientHTTP(clientAPI, domain, opts...)
 }
 
-func NewClientHTTP(clientAPI ClientAPI, domain string) ClientHTTP {
-	return &clientHttp{
+func newClientHTTP(clientAPI ClientAPI, domain string, opts ...Option) *clientHttp {
+	c := &clientHttp{
 		domain: domain,
 		client: clientAPI,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	middlewares := c.middlewares
+	if c.log != nil {
+		middlewares = append([]Middleware{loggingMiddleware(c.log)}, middlewares...)
+	}
+
+	if c.telemetry != nil {
+		middlewares = append([]Middleware{telemetryMiddleware(*c.telemetry)}, middlewares...)
+	}
+
+	if c.auth != nil {
+		middlewares = append(middlewares[:len(middlewares):len(middlewares)], authMiddleware(c.auth))
+	}
+
+	if c.signer != nil {
+		middlewares = append(middlewares[:len(middlewares):len(middlewares)], signMiddleware(c.signer))
+	}
+
+	c.transport = chain(c.client, middlewares)
+	if c.attemptTimeout > 0 {
+		c.transport = newAttemptTimeoutClient(c.transport, c.attemptTimeout)
+	}
+
+	if c.breaker != nil {
+		c.transport = newCircuitBreaker(c.transport, c.domain, *c.breaker)
+	}
+
+	if c.rateLimit != nil {
+		c.transport = newRateLimitClient(c.transport, *c.rateLimit)
+	}
+
+	if c.hedge != nil {
+		c.transport = newHedgeClient(c.transport, *c.hedge)
+	}
+
+	if c.retry != nil {
+		c.transport = newRetryClient(c.transport, *c.retry)
+	}
+
+	if c.cache != nil {
+		c.transport = newCacheClient(c.transport, *c.cache)
+	}
+
+	if c.idempotency != nil {
+		c.transport = newIdempotencyClient(c.transport, *c.idempotency)
+	}
+
+	return c
+}
+
+type response struct {
+	body       []byte
+	statusCode int
+	header     http.Header
+	request    *http.Request
 }
 
 func (c *clientHttp) Do(ctx context.Context, req *http.Request) (response []byte, statusCode int, err error) {
-	return c.do(ctx, req, 0)
+	resp, err := c.do(ctx, req, 0)
+	if err != nil {
+		return
+	}
+
+	return resp.body, resp.statusCode, nil
 }
 
 func (c *clientHttp) DoWithTimeout(ctx context.Context, req *http.Request, timeout int64, expectedCode int, out interface{}) error {
-	resp, statusCode, err := c.do(ctx, req, timeout)
+	resp, err := c.do(ctx, req, time.Duration(timeout)*time.Millisecond)
 	if err != nil {
 		return err
 	}
 
-	if statusCode != expectedCode {
-		return fmt.Errorf("status Code [ %d ], err: %v", statusCode, err)
-	}
+	_, err = NewResponseHandler().On(expectedCode, out).handle(resp)
+
+	return err
+}
 
-	err = json.Unmarshal(resp, &out)
+func (c *clientHttp) Handle(ctx context.Context, req *http.Request, handler ResponseHandler) (*HandledResponse, error) {
+	resp, err := c.do(ctx, req, 0)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return handler.handle(resp)
 }
 
-func (c *clientHttp) do(ctx context.Context, req *http.Request, timeout int64) (response []byte, statusCode int, err error) {
+func (c *clientHttp) do(ctx context.Context, req *http.Request, timeout time.Duration) (*response, error) {
+	if timeout <= 0 {
+		timeout = c.timeout
+	}
+
 	if timeout > 0 {
-		ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+		ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
 		ctx = ctxWithTimeout
 	}
 
-	url := c.domain + req.URL.Path
+	resp, req, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(c.limitBody(resp.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	return &response{
+		body:       body,
+		statusCode: resp.StatusCode,
+		header:     resp.Header,
+		request:    req,
+	}, nil
+}
+
+func (c *clientHttp) send(ctx context.Context, req *http.Request) (*http.Response, *http.Request, error) {
+	if err := buildError(req); err != nil {
+		return nil, nil, err
+	}
+
+	url := c.domain + req.URL.EscapedPath()
 	if req.URL.RawQuery != "" {
 		url += "?" + req.URL.RawQuery
 	}
 
+	var err error
+
 	req.URL, err = req.URL.Parse(url)
 	if err != nil {
-		return
+		return nil, nil, err
 	}
 
 	req = req.WithContext(ctx)
 
-	resp, err := c.client.Do(req)
+	resp, err := c.transport.Do(req)
 	if err != nil {
-		return
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return
+		return nil, nil, err
 	}
 
-	return body, resp.StatusCode, nil
+	return resp, req, nil
 }
@@ -2,12 +2,14 @@ package clienthttp_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/dot-backend/synergetic-craft/clienthttp"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"net/http"
 	"net/http/httptest"
-	"synergetic-craft/clienthttp"
+	"strings"
 	"testing"
 	"time"
 )
@@ -55,7 +57,7 @@ func TestClientHttp_Do(t *testing.T) {
 			WithBodyBytes([]byte("body byte")).
 			Build()
 
-		client := clienthttp.NewClientHTTP(&http.Client{Timeout: 100}, ts.URL)
+		client := clienthttp.NewClientHTTP(&http.Client{Timeout: 100 * time.Millisecond}, ts.URL)
 
 		_, _, err := client.Do(context.TODO(), req)
 
@@ -200,3 +202,57 @@ func TestClientHttp_DoWithTimeout(t *testing.T) {
 		assert.Contains(t, err.Error(), "status Code [ 400 ]")
 	})
 }
+
+func TestClientHttp_DoWithTimeout_HTTPError(t *testing.T) {
+	t.Run("should return HTTPError with the upstream payload [NOT FOUND]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusNotFound)
+
+			fmt.Fprintf(w, `{"title":"user not found"}`)
+		}))
+		defer ts.Close()
+
+		req := clienthttp.NewRequest(http.MethodGet, "/unit-test/users/1").
+			WithQueryParam("query-param", "param").
+			Build()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL)
+
+		var out struct{}
+
+		err := client.DoWithTimeout(context.TODO(), req, 100, http.StatusOK, &out)
+
+		var httpErr *clienthttp.HTTPError
+
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+		assert.Equal(t, http.MethodGet, httpErr.Method)
+		assert.Equal(t, ts.URL+"/unit-test/users/1?query-param=param", httpErr.URL)
+		assert.Equal(t, "application/problem+json", httpErr.Header.Get("Content-Type"))
+		assert.Equal(t, `{"title":"user not found"}`, string(httpErr.Body))
+		assert.NotContains(t, err.Error(), "<nil>")
+	})
+
+	t.Run("should bound the body kept in HTTPError [LARGE BODY]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			fmt.Fprint(w, strings.Repeat("x", 4096))
+		}))
+		defer ts.Close()
+
+		req := clienthttp.NewRequest(http.MethodGet, "/unit-test/users").Build()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL)
+
+		var out struct{}
+
+		err := client.DoWithTimeout(context.TODO(), req, 100, http.StatusOK, &out)
+
+		var httpErr *clienthttp.HTTPError
+
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, 1024, len(httpErr.Body))
+	})
+}
@@ -0,0 +1,183 @@
+package clienthttp
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeXML      = "application/xml"
+	ContentTypeForm     = "application/x-www-form-urlencoded"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeMsgpack  = "application/msgpack"
+)
+
+type Codec interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	JSONCodec     Codec = jsonCodec{}
+	XMLCodec      Codec = xmlCodec{}
+	FormCodec     Codec = formCodec{}
+	ProtobufCodec Codec = protobufCodec{}
+	MsgpackCodec  Codec = msgpackCodec{}
+)
+
+var codecs = struct {
+	sync.RWMutex
+	byType map[string]Codec
+}{
+	byType: map[string]Codec{
+		ContentTypeJSON:           JSONCodec,
+		ContentTypeXML:            XMLCodec,
+		"text/xml":                XMLCodec,
+		ContentTypeForm:           FormCodec,
+		ContentTypeProtobuf:       ProtobufCodec,
+		"application/protobuf":    ProtobufCodec,
+		ContentTypeMsgpack:        MsgpackCodec,
+		"application/x-msgpack":   MsgpackCodec,
+		"application/vnd.msgpack": MsgpackCodec,
+	},
+}
+
+func RegisterCodec(contentType string, codec Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	codecs.byType[strings.ToLower(contentType)] = codec
+}
+
+func CodecFor(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	codecs.RLock()
+	defer codecs.RUnlock()
+
+	if codec, ok := codecs.byType[mediaType]; ok {
+		return codec, true
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return codecs.byType[ContentTypeJSON], true
+	case strings.HasSuffix(mediaType, "+xml"):
+		return codecs.byType[ContentTypeXML], true
+	}
+
+	return nil, false
+}
+
+func decodeBody(contentType string, body []byte, out interface{}) error {
+	codec, ok := CodecFor(contentType)
+	if !ok {
+		codec = JSONCodec
+	}
+
+	return codec.Unmarshal(body, out)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string { return ContentTypeJSON }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+type xmlCodec struct{}
+
+func (xmlCodec) ContentType() string { return ContentTypeXML }
+
+func (xmlCodec) Marshal(v interface{}) ([]byte, error) { return xml.Marshal(v) }
+
+func (xmlCodec) Unmarshal(data []byte, v interface{}) error { return xml.Unmarshal(data, v) }
+
+type formCodec struct{}
+
+func (formCodec) ContentType() string { return ContentTypeForm }
+
+func (formCodec) Marshal(v interface{}) ([]byte, error) {
+	switch values := v.(type) {
+	case url.Values:
+		return []byte(values.Encode()), nil
+	case map[string][]string:
+		return []byte(url.Values(values).Encode()), nil
+	case map[string]string:
+		form := url.Values{}
+		for key, value := range values {
+			form.Set(key, value)
+		}
+
+		return []byte(form.Encode()), nil
+	}
+
+	return nil, fmt.Errorf("form codec [ %T ] is not supported", v)
+}
+
+func (formCodec) Unmarshal(data []byte, v interface{}) error {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+
+	switch out := v.(type) {
+	case *url.Values:
+		*out = values
+	case *map[string][]string:
+		*out = values
+	case *map[string]string:
+		*out = make(map[string]string, len(values))
+		for key := range values {
+			(*out)[key] = values.Get(key)
+		}
+	default:
+		return fmt.Errorf("form codec [ %T ] is not supported", v)
+	}
+
+	return nil
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) ContentType() string { return ContentTypeProtobuf }
+
+func (protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("protobuf codec [ %T ] is not a proto.Message", v)
+	}
+
+	return proto.Marshal(msg)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("protobuf codec [ %T ] is not a proto.Message", v)
+	}
+
+	return proto.Unmarshal(data, msg)
+}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) ContentType() string { return ContentTypeMsgpack }
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) { return msgpack.Marshal(v) }
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error { return msgpack.Unmarshal(data, v) }
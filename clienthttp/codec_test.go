@@ -0,0 +1,172 @@
+package clienthttp_test
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type xmlUserFixture struct {
+	XMLName xml.Name `xml:"user"`
+	ID      int      `xml:"id"`
+	Name    string   `xml:"name"`
+}
+
+func TestHTTPRequestBuilder_Codecs(t *testing.T) {
+	t.Run("should encode JSON body and set Content-Type [JSON]", func(t *testing.T) {
+		request := clienthttp.NewRequest(http.MethodPost, "/test/test-1").
+			WithJSONBody(userFixture{ID: 1, Name: "diego"}).
+			Build()
+
+		body, _ := io.ReadAll(request.Body)
+
+		assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+		assert.JSONEq(t, `{"id":1,"name":"diego"}`, string(body))
+	})
+
+	t.Run("should encode XML body and set Content-Type [XML]", func(t *testing.T) {
+		request := clienthttp.NewRequest(http.MethodPost, "/test/test-1").
+			WithXMLBody(xmlUserFixture{ID: 1, Name: "diego"}).
+			Build()
+
+		body, _ := io.ReadAll(request.Body)
+
+		assert.Equal(t, "application/xml", request.Header.Get("Content-Type"))
+		assert.Equal(t, "<user><id>1</id><name>diego</name></user>", string(body))
+	})
+
+	t.Run("should encode form body and set Content-Type [FORM]", func(t *testing.T) {
+		request := clienthttp.NewRequest(http.MethodPost, "/test/test-1").
+			WithFormBody(url.Values{"grant_type": {"client_credentials"}, "scope": {"read write"}}).
+			Build()
+
+		body, _ := io.ReadAll(request.Body)
+
+		assert.Equal(t, "application/x-www-form-urlencoded", request.Header.Get("Content-Type"))
+		assert.Equal(t, "grant_type=client_credentials&scope=read+write", string(body))
+	})
+
+	t.Run("should encode protobuf body with a registered codec [PROTOBUF]", func(t *testing.T) {
+		request := clienthttp.NewRequest(http.MethodPost, "/test/test-1").
+			WithBody(clienthttp.ProtobufCodec, wrapperspb.String("diego")).
+			Build()
+
+		body, _ := io.ReadAll(request.Body)
+
+		var out wrapperspb.StringValue
+
+		assert.Equal(t, "application/x-protobuf", request.Header.Get("Content-Type"))
+		assert.NoError(t, proto.Unmarshal(body, &out))
+		assert.Equal(t, "diego", out.GetValue())
+	})
+
+	t.Run("should return marshal errors from BuildE [ERROR]", func(t *testing.T) {
+		req, err := clienthttp.NewRequest(http.MethodPost, "/test/test-1").
+			WithBody(clienthttp.ProtobufCodec, userFixture{}).
+			BuildE()
+
+		assert.Nil(t, req)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a proto.Message")
+	})
+}
+
+func TestClientHttp_DecodeByContentType(t *testing.T) {
+	newServer := func(contentType string, body []byte) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			_, _ = w.Write(body)
+		}))
+	}
+
+	t.Run("should decode XML responses [XML]", func(t *testing.T) {
+		ts := newServer("text/xml; charset=utf-8", []byte(`<user><id>3</id><name>diego</name></user>`))
+		defer ts.Close()
+
+		user, err := clienthttp.Get[xmlUserFixture](context.TODO(), clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient), "/users/3")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, user.ID)
+		assert.Equal(t, "diego", user.Name)
+	})
+
+	t.Run("should decode form responses [FORM]", func(t *testing.T) {
+		ts := newServer("application/x-www-form-urlencoded", []byte(`access_token=abc&expires_in=60`))
+		defer ts.Close()
+
+		values, err := clienthttp.Get[url.Values](context.TODO(), clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient), "/token")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", values.Get("access_token"))
+	})
+
+	t.Run("should decode msgpack responses [MSGPACK]", func(t *testing.T) {
+		body, _ := msgpack.Marshal(map[string]interface{}{"id": 5, "name": "diego"})
+
+		ts := newServer("application/msgpack", body)
+		defer ts.Close()
+
+		var user struct {
+			ID   int    `msgpack:"id"`
+			Name string `msgpack:"name"`
+		}
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		_, err := client.Handle(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/users/5").Build(),
+			clienthttp.NewResponseHandler().OnSuccess(&user))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, user.ID)
+	})
+
+	t.Run("should decode structured JSON suffixes and fall back to JSON [FALLBACK]", func(t *testing.T) {
+		for _, contentType := range []string{"application/problem+json", "text/plain"} {
+			ts := newServer(contentType, []byte(`{"id":9,"name":"diego"}`))
+
+			user, err := clienthttp.Get[userFixture](context.TODO(), clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient), "/users/9")
+
+			assert.NoError(t, err, contentType)
+			assert.Equal(t, 9, user.ID, contentType)
+
+			ts.Close()
+		}
+	})
+}
+
+type upperCodec struct{}
+
+func (upperCodec) ContentType() string { return "application/x-upper" }
+
+func (upperCodec) Marshal(v interface{}) ([]byte, error) { return []byte(fmt.Sprint(v)), nil }
+
+func (upperCodec) Unmarshal(data []byte, v interface{}) error {
+	*(v.(*string)) = string(data) + "!"
+	return nil
+}
+
+func TestRegisterCodec(t *testing.T) {
+	t.Run("should resolve registered codecs by media type", func(t *testing.T) {
+		clienthttp.RegisterCodec("application/x-upper", upperCodec{})
+
+		codec, ok := clienthttp.CodecFor("application/x-upper; charset=utf-8")
+
+		assert.True(t, ok)
+		assert.Equal(t, "application/x-upper", codec.ContentType())
+
+		_, ok = clienthttp.CodecFor("application/unknown")
+
+		assert.False(t, ok)
+	})
+}
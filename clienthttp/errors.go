@@ -0,0 +1,39 @@
+package clienthttp
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const errorBodyLimit = 1024
+
+type HTTPError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Header     http.Header
+	Body       []byte
+}
+
+func newHTTPError(resp *response) *HTTPError {
+	body := resp.body
+	if len(body) > errorBodyLimit {
+		body = body[:errorBodyLimit]
+	}
+
+	return &HTTPError{
+		StatusCode: resp.statusCode,
+		Method:     resp.request.Method,
+		URL:        resp.request.URL.Redacted(),
+		Header:     resp.header,
+		Body:       append([]byte(nil), body...),
+	}
+}
+
+func (e *HTTPError) Error() string {
+	if len(e.Body) == 0 {
+		return fmt.Sprintf("status Code [ %d ], %s %s", e.StatusCode, e.Method, e.URL)
+	}
+
+	return fmt.Sprintf("status Code [ %d ], %s %s, body: %s", e.StatusCode, e.Method, e.URL, e.Body)
+}
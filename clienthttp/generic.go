@@ -0,0 +1,43 @@
+package clienthttp
+
+import (
+	"context"
+	"net/http"
+)
+
+func Send[T any](ctx context.Context, c HandlerClient, builder HTTPRequestBuilder) (T, error) {
+	var out T
+
+	req, err := builder.BuildE()
+	if err != nil {
+		return out, err
+	}
+
+	_, err = c.Handle(ctx, req, NewResponseHandler().OnSuccess(&out))
+
+	return out, err
+}
+
+func Get[T any](ctx context.Context, c HandlerClient, url string) (T, error) {
+	return Send[T](ctx, c, NewRequest(http.MethodGet, url))
+}
+
+func Delete[T any](ctx context.Context, c HandlerClient, url string) (T, error) {
+	return Send[T](ctx, c, NewRequest(http.MethodDelete, url))
+}
+
+func Post[Req, Resp any](ctx context.Context, c HandlerClient, url string, body Req) (Resp, error) {
+	return sendWithBody[Req, Resp](ctx, c, http.MethodPost, url, body)
+}
+
+func Put[Req, Resp any](ctx context.Context, c HandlerClient, url string, body Req) (Resp, error) {
+	return sendWithBody[Req, Resp](ctx, c, http.MethodPut, url, body)
+}
+
+func Patch[Req, Resp any](ctx context.Context, c HandlerClient, url string, body Req) (Resp, error) {
+	return sendWithBody[Req, Resp](ctx, c, http.MethodPatch, url, body)
+}
+
+func sendWithBody[Req, Resp any](ctx context.Context, c HandlerClient, method, url string, body Req) (Resp, error) {
+	return Send[Resp](ctx, c, NewRequest(method, url).WithJSONBody(body))
+}
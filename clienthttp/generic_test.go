@@ -0,0 +1,87 @@
+package clienthttp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenericHelpers(t *testing.T) {
+	t.Run("should decode typed response with Get [GET]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/unit-test/users/1", r.URL.Path)
+
+			fmt.Fprintf(w, `{"id":1,"name":"diego"}`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		user, err := clienthttp.Get[userFixture](context.TODO(), client, "/unit-test/users/1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, userFixture{ID: 1, Name: "diego"}, user)
+	})
+
+	t.Run("should marshal the request and decode the response with Post [POST]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.JSONEq(t, `{"id":0,"name":"diego"}`, string(body))
+
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":7,"name":"diego"}`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		user, err := clienthttp.Post[userFixture, userFixture](context.TODO(), client, "/unit-test/users", userFixture{Name: "diego"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, user.ID)
+	})
+
+	t.Run("should send a builder and decode slices with Send [SEND]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "limit=2", r.URL.RawQuery)
+
+			fmt.Fprintf(w, `[{"id":1},{"id":2}]`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		users, err := clienthttp.Send[[]userFixture](context.TODO(), client,
+			clienthttp.NewRequest(http.MethodGet, "/unit-test/users").WithQueryParam("limit", "2"))
+
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+	})
+
+	t.Run("should return HTTPError when status is not successful [ERROR]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		_, err := clienthttp.Delete[struct{}](context.TODO(), client, "/unit-test/users/1")
+
+		var httpErr *clienthttp.HTTPError
+
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	})
+}
@@ -0,0 +1,209 @@
+package clienthttp
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type HedgeConfig struct {
+	Delay      time.Duration
+	Percentile float64
+	MinSamples int
+	Window     int
+	MaxHedges  int
+}
+
+func WithHedging(conf HedgeConfig) Option {
+	return func(c *clientHttp) {
+		c.hedge = &conf
+	}
+}
+
+func (conf HedgeConfig) withDefaults() HedgeConfig {
+	if conf.MaxHedges < 1 {
+		conf.MaxHedges = 1
+	}
+
+	if conf.MinSamples < 1 {
+		conf.MinSamples = 20
+	}
+
+	if conf.Window < conf.MinSamples {
+		conf.Window = int(math.Max(100, float64(conf.MinSamples)))
+	}
+
+	return conf
+}
+
+type hedgeClient struct {
+	next ClientAPI
+	conf HedgeConfig
+
+	mu      sync.Mutex
+	samples []time.Duration
+	pos     int
+}
+
+type hedgeResult struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+func newHedgeClient(next ClientAPI, conf HedgeConfig) *hedgeClient {
+	conf = conf.withDefaults()
+
+	return &hedgeClient{
+		next:    next,
+		conf:    conf,
+		samples: make([]time.Duration, 0, conf.Window),
+	}
+}
+
+func (h *hedgeClient) Do(req *http.Request) (*http.Response, error) {
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || !isReplayable(req) {
+		return h.next.Do(req)
+	}
+
+	ctx := req.Context()
+	results := make(chan hedgeResult, h.conf.MaxHedges+1)
+	cancels := make([]context.CancelFunc, 0, h.conf.MaxHedges+1)
+
+	launch := func() error {
+		attemptCtx, cancel := context.WithCancel(ctx)
+
+		attemptReq, err := replayRequest(req.WithContext(attemptCtx))
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+
+		go func() {
+			start := time.Now()
+
+			resp, err := h.next.Do(attemptReq)
+			if err == nil {
+				h.observe(time.Since(start))
+			}
+
+			results <- hedgeResult{index: index, resp: resp, err: err}
+		}()
+
+		return nil
+	}
+
+	if err := launch(); err != nil {
+		return nil, err
+	}
+
+	pending := 1
+
+	var hedge <-chan time.Time
+	if delay := h.delay(); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		hedge = timer.C
+	}
+
+	for {
+		select {
+		case result := <-results:
+			pending--
+
+			if result.err != nil && pending > 0 {
+				cancels[result.index]()
+				continue
+			}
+
+			h.settle(cancels, result.index, results, pending)
+
+			if result.err != nil {
+				cancels[result.index]()
+				return nil, result.err
+			}
+
+			result.resp.Body = &cancelBody{ReadCloser: result.resp.Body, cancel: cancels[result.index]}
+
+			return result.resp, nil
+		case <-hedge:
+			hedge = nil
+
+			if err := launch(); err != nil {
+				continue
+			}
+
+			pending++
+
+			if len(cancels) <= h.conf.MaxHedges {
+				timer := time.NewTimer(h.delay())
+				defer timer.Stop()
+
+				hedge = timer.C
+			}
+		case <-ctx.Done():
+			h.settle(cancels, -1, results, pending)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// settle cancels every attempt but the winner and drains the ones still in
+// flight so their connections are released.
+func (h *hedgeClient) settle(cancels []context.CancelFunc, winner int, results <-chan hedgeResult, pending int) {
+	for i, cancel := range cancels {
+		if i != winner {
+			cancel()
+		}
+	}
+
+	if pending == 0 {
+		return
+	}
+
+	go func() {
+		for i := 0; i < pending; i++ {
+			result := <-results
+			discardResponse(result.resp)
+		}
+	}()
+}
+
+func (h *hedgeClient) observe(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) < h.conf.Window {
+		h.samples = append(h.samples, latency)
+		return
+	}
+
+	h.samples[h.pos] = latency
+	h.pos = (h.pos + 1) % h.conf.Window
+}
+
+func (h *hedgeClient) delay() time.Duration {
+	if h.conf.Percentile <= 0 || h.conf.Percentile >= 1 {
+		return h.conf.Delay
+	}
+
+	h.mu.Lock()
+	if len(h.samples) < h.conf.MinSamples {
+		h.mu.Unlock()
+		return h.conf.Delay
+	}
+
+	samples := append([]time.Duration(nil), h.samples...)
+	h.mu.Unlock()
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	return samples[int(math.Ceil(h.conf.Percentile*float64(len(samples))))-1]
+}
@@ -0,0 +1,196 @@
+package clienthttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientHttp_WithHedging(t *testing.T) {
+	t.Run("should take the hedged response and cancel the slow attempt [HEDGE]", func(t *testing.T) {
+		var calls int32
+		cancelled := make(chan struct{})
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			call := atomic.AddInt32(&calls, 1)
+			if call == 1 {
+				select {
+				case <-r.Context().Done():
+					close(cancelled)
+				case <-time.After(5 * time.Second):
+				}
+
+				return
+			}
+
+			fmt.Fprintf(w, "attempt %d", call)
+		}))
+		defer ts.Close()
+
+		client := NewClientHTTP(http.DefaultClient, ts.URL, WithHedging(HedgeConfig{Delay: 20 * time.Millisecond}))
+
+		start := time.Now()
+
+		body, statusCode, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, "attempt 2", string(body))
+		assert.Less(t, time.Since(start), time.Second)
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("slow attempt was not cancelled")
+		}
+	})
+
+	t.Run("should not hedge when the first attempt answers in time [FAST]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+		}))
+		defer ts.Close()
+
+		client := NewClientHTTP(http.DefaultClient, ts.URL, WithHedging(HedgeConfig{Delay: 200 * time.Millisecond}))
+
+		_, _, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+
+		assert.NoError(t, err)
+		time.Sleep(250 * time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should never hedge writes [POST]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(50 * time.Millisecond)
+		}))
+		defer ts.Close()
+
+		client := NewClientHTTP(http.DefaultClient, ts.URL, WithHedging(HedgeConfig{Delay: 5 * time.Millisecond}))
+
+		_, _, err := client.Do(context.TODO(), NewRequest(http.MethodPost, "/users").WithJSONBody(map[string]int{"id": 1}).Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should wait for the hedge when the first attempt fails [ERROR]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			fmt.Fprint(w, "ok")
+		}))
+		defer ts.Close()
+
+		var attempts int32
+
+		transport := ClientAPIFunc(func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				time.Sleep(30 * time.Millisecond)
+				return nil, fmt.Errorf("connection reset")
+			}
+
+			return http.DefaultClient.Do(req)
+		})
+
+		client := NewClientHTTP(transport, ts.URL, WithHedging(HedgeConfig{Delay: 10 * time.Millisecond}))
+
+		body, _, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", string(body))
+	})
+}
+
+func TestHedgeClient_Delay(t *testing.T) {
+	t.Run("should use the configured delay until there are enough samples", func(t *testing.T) {
+		h := newHedgeClient(nil, HedgeConfig{Delay: 50 * time.Millisecond, Percentile: 0.9, MinSamples: 10})
+
+		for i := 1; i < 10; i++ {
+			h.observe(time.Duration(i) * time.Millisecond)
+		}
+
+		assert.Equal(t, 50*time.Millisecond, h.delay())
+
+		h.observe(10 * time.Millisecond)
+
+		assert.Equal(t, 9*time.Millisecond, h.delay())
+	})
+
+	t.Run("should keep only the latest samples in the window", func(t *testing.T) {
+		h := newHedgeClient(nil, HedgeConfig{Percentile: 0.5, MinSamples: 2, Window: 2})
+
+		for _, latency := range []time.Duration{time.Second, time.Second, time.Millisecond, time.Millisecond} {
+			h.observe(latency)
+		}
+
+		assert.Equal(t, time.Millisecond, h.delay())
+	})
+}
+
+func TestClientHttp_Timeouts(t *testing.T) {
+	t.Run("should retry an attempt that exceeds its own deadline [ATTEMPT]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+
+				return
+			}
+
+			fmt.Fprint(w, "ok")
+		}))
+		defer ts.Close()
+
+		client := NewClientHTTP(http.DefaultClient, ts.URL,
+			WithAttemptTimeout(50*time.Millisecond),
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+		)
+
+		body, statusCode, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, "ok", string(body))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should bound the whole call with the overall deadline [OVERALL]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		defer ts.Close()
+
+		client := NewClientHTTP(http.DefaultClient, ts.URL,
+			WithTimeout(100*time.Millisecond),
+			WithAttemptTimeout(30*time.Millisecond),
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Millisecond}),
+		)
+
+		start := time.Now()
+
+		_, _, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
@@ -0,0 +1,303 @@
+package httpstub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+)
+
+// Request is a copy of a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	// Stub is the index of the stub that answered, or -1 when none matched.
+	Stub int
+}
+
+// Server is an httptest.Server answering from declarative stubs. Stubs are
+// evaluated in the order they were added and the first match answers.
+type Server struct {
+	*httptest.Server
+
+	t        testing.TB
+	mu       sync.Mutex
+	stubs    []*Stub
+	requests []Request
+}
+
+// New starts a plain HTTP server closed on test cleanup.
+func New(t testing.TB, stubs ...*Stub) *Server {
+	s := &Server{t: t, stubs: stubs}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// NewTLS starts a TLS server; Client returns an http.Client trusting it.
+func NewTLS(t testing.TB, stubs ...*Stub) *Server {
+	s := &Server{t: t, stubs: stubs}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *Server) Stub(stubs ...*Stub) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stubs = append(s.stubs, stubs...)
+}
+
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo filters the received requests by method and path pattern.
+func (s *Server) RequestsTo(method, pattern string) []Request {
+	var requests []Request
+
+	for _, req := range s.Requests() {
+		if ok, _ := path.Match(pattern, req.Path); ok && req.Method == method {
+			requests = append(requests, req)
+		}
+	}
+
+	return requests
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	index, stub := s.match(r, body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+		Stub:   index,
+	})
+	s.mu.Unlock()
+
+	if stub == nil {
+		s.t.Errorf("httpstub: no stub matches %s %s", r.Method, r.URL)
+		http.Error(w, fmt.Sprintf("httpstub: no stub matches %s %s", r.Method, r.URL), http.StatusNotImplemented)
+
+		return
+	}
+
+	stub.respond(w, r)
+}
+
+func (s *Server) match(r *http.Request, body []byte) (int, *Stub) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, stub := range s.stubs {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if stub.exhausted() || !stub.matches(r) {
+			continue
+		}
+
+		stub.served++
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		return i, stub
+	}
+
+	return -1, nil
+}
+
+// Stub describes the requests a route accepts and how it answers them.
+type Stub struct {
+	method   string
+	pattern  string
+	matchers []clienthttp.RequestMatcher
+	times    int
+	served   int
+
+	status  int
+	header  http.Header
+	body    []byte
+	delay   time.Duration
+	handler http.HandlerFunc
+}
+
+// On accepts requests with method and a path matching pattern, using
+// path.Match syntax such as "/users/*".
+func On(method, pattern string) *Stub {
+	return &Stub{method: method, pattern: pattern, status: http.StatusOK, header: make(http.Header)}
+}
+
+func Get(pattern string) *Stub {
+	return On(http.MethodGet, pattern)
+}
+
+func Post(pattern string) *Stub {
+	return On(http.MethodPost, pattern)
+}
+
+func Put(pattern string) *Stub {
+	return On(http.MethodPut, pattern)
+}
+
+func Patch(pattern string) *Stub {
+	return On(http.MethodPatch, pattern)
+}
+
+func Delete(pattern string) *Stub {
+	return On(http.MethodDelete, pattern)
+}
+
+func (s *Stub) WithQuery(key, value string) *Stub {
+	return s.Matching(clienthttp.HasQuery(url.Values{key: {value}}))
+}
+
+func (s *Stub) WithHeader(key, value string) *Stub {
+	return s.Matching(clienthttp.HasHeaders(http.Header{http.CanonicalHeaderKey(key): {value}}))
+}
+
+func (s *Stub) WithBody(body string) *Stub {
+	return s.Matching(func(r *http.Request) error {
+		actual, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(actual))
+
+		if string(actual) != body {
+			return fmt.Errorf("body %q doesn't match %q", actual, body)
+		}
+
+		return nil
+	})
+}
+
+// WithJSONBody compares the body as decoded JSON.
+func (s *Stub) WithJSONBody(body interface{}) *Stub {
+	return s.Matching(clienthttp.JSONBodyEquals(body))
+}
+
+// Matching adds any clienthttp request matcher, such as JSONPathEquals.
+func (s *Stub) Matching(matchers ...clienthttp.RequestMatcher) *Stub {
+	s.matchers = append(s.matchers, matchers...)
+	return s
+}
+
+// Times lets the stub answer n requests, after which later stubs are tried.
+func (s *Stub) Times(n int) *Stub {
+	s.times = n
+	return s
+}
+
+func (s *Stub) Status(status int) *Stub {
+	s.status = status
+	return s
+}
+
+func (s *Stub) Header(key, value string) *Stub {
+	s.header.Add(key, value)
+	return s
+}
+
+func (s *Stub) Body(body string) *Stub {
+	s.body = []byte(body)
+	return s
+}
+
+func (s *Stub) JSON(body interface{}) *Stub {
+	data, err := json.Marshal(body)
+	if err != nil {
+		panic(fmt.Sprintf("httpstub: encoding JSON body: %v", err))
+	}
+
+	s.header.Set("Content-Type", "application/json")
+	s.body = data
+
+	return s
+}
+
+// Delay holds the response, unless the client gives up first.
+func (s *Stub) Delay(d time.Duration) *Stub {
+	s.delay = d
+	return s
+}
+
+func (s *Stub) RedirectTo(status int, location string) *Stub {
+	s.status = status
+	s.header.Set("Location", location)
+
+	return s
+}
+
+// Handler answers with h instead of the static response.
+func (s *Stub) Handler(h http.HandlerFunc) *Stub {
+	s.handler = h
+	return s
+}
+
+func (s *Stub) exhausted() bool {
+	return s.times > 0 && s.served >= s.times
+}
+
+func (s *Stub) matches(r *http.Request) bool {
+	if s.method != r.Method {
+		return false
+	}
+
+	if ok, err := path.Match(s.pattern, r.URL.Path); err != nil || !ok {
+		return false
+	}
+
+	return clienthttp.MatchAll(s.matchers...)(r) == nil
+}
+
+func (s *Stub) respond(w http.ResponseWriter, r *http.Request) {
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if s.handler != nil {
+		s.handler(w, r)
+		return
+	}
+
+	for key, values := range s.header {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+
+	w.WriteHeader(s.status)
+	_, _ = w.Write(s.body)
+}
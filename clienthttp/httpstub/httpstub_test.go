@@ -0,0 +1,109 @@
+package httpstub_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/dot-backend/synergetic-craft/clienthttp/httpstub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	t.Run("should answer from the first matching stub and record requests [MATCH]", func(t *testing.T) {
+		server := httpstub.New(t,
+			httpstub.Get("/users/*").WithHeader("X-Tenant", "acme").JSON(map[string]int{"id": 1}),
+			httpstub.Post("/users").WithJSONBody(map[string]string{"name": "diego"}).Status(http.StatusCreated),
+		)
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, server.URL)
+
+		body, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/users/1").WithHeader("X-Tenant", "acme").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.JSONEq(t, `{"id":1}`, string(body))
+
+		_, statusCode, err = client.Do(context.TODO(), clienthttp.NewRequest(http.MethodPost, "/users").WithBodyBytes([]byte(`{ "name": "diego" }`)).Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, statusCode)
+
+		requests := server.RequestsTo(http.MethodPost, "/users")
+		assert.Len(t, server.Requests(), 2)
+		assert.Len(t, requests, 1)
+		assert.Equal(t, 1, requests[0].Stub)
+		assert.Equal(t, `{ "name": "diego" }`, string(requests[0].Body))
+	})
+
+	t.Run("should fall through exhausted stubs to build sequences [TIMES]", func(t *testing.T) {
+		server := httpstub.New(t,
+			httpstub.Get("/users").Status(http.StatusServiceUnavailable).Times(2),
+			httpstub.Get("/users").Body("ok"),
+		)
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, server.URL,
+			clienthttp.WithRetryPolicy(clienthttp.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+		)
+
+		body, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/users").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, "ok", string(body))
+		assert.Len(t, server.Requests(), 3)
+	})
+
+	t.Run("should delay responses to exercise client timeouts [DELAY]", func(t *testing.T) {
+		server := httpstub.New(t, httpstub.Get("/slow").Delay(time.Second))
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, server.URL, clienthttp.WithTimeout(50*time.Millisecond))
+
+		_, _, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/slow").Build())
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should serve TLS and redirects [TLS]", func(t *testing.T) {
+		server := httpstub.NewTLS(t,
+			httpstub.Get("/old").RedirectTo(http.StatusMovedPermanently, "/new"),
+			httpstub.Get("/new").Handler(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(r.URL.Path))
+			}),
+		)
+
+		client := clienthttp.NewClientHTTP(server.Client(), server.URL)
+
+		body, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/old").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, "/new", string(body))
+	})
+
+	t.Run("should report requests no stub matches [UNMATCHED]", func(t *testing.T) {
+		recorder := &testingRecorder{TB: t}
+		server := httpstub.New(recorder, httpstub.Get("/users"))
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, server.URL)
+
+		_, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodDelete, "/users").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotImplemented, statusCode)
+		assert.Equal(t, []string{"httpstub: no stub matches DELETE /users"}, recorder.errors)
+		assert.Equal(t, -1, server.Requests()[0].Stub)
+	})
+}
+
+type testingRecorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *testingRecorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
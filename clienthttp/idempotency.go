@@ -0,0 +1,136 @@
+package clienthttp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	defaultIdempotencyKeysTTL = 24 * time.Hour
+)
+
+type IdempotencyConfig struct {
+	// Store keeps the final response of each key, e.g. NewRedisCacheStore, so
+	// a re-executed operation replays it instead of calling the server again.
+	Store CacheStore
+	// TTL defaults to 24 hours, the retention most payment partners apply.
+	TTL time.Duration
+	// GenerateKeys adds a fresh key to POST and PATCH requests sent without
+	// one, making them retryable.
+	GenerateKeys bool
+}
+
+func WithIdempotency(conf IdempotencyConfig) Option {
+	return func(c *clientHttp) {
+		c.idempotency = &conf
+	}
+}
+
+// WithIdempotencyKey sets the key of the logical operation; reuse the same
+// key, e.g. a job or payment id, when the operation is executed again.
+func (h HTTPRequestBuilder) WithIdempotencyKey(key string) HTTPRequestBuilder {
+	return h.WithHeader(HeaderIdempotencyKey, key)
+}
+
+// WithNewIdempotencyKey generates a key once, so every request built from
+// the returned builder carries the same one.
+func (h HTTPRequestBuilder) WithNewIdempotencyKey() HTTPRequestBuilder {
+	return h.WithIdempotencyKey(newIdempotencyKey())
+}
+
+func hasIdempotencyKey(req *http.Request) bool {
+	return req.Header.Get(HeaderIdempotencyKey) != ""
+}
+
+type idempotencyClient struct {
+	next ClientAPI
+	conf IdempotencyConfig
+}
+
+func newIdempotencyClient(next ClientAPI, conf IdempotencyConfig) *idempotencyClient {
+	if conf.TTL <= 0 {
+		conf.TTL = defaultIdempotencyKeysTTL
+	}
+
+	return &idempotencyClient{
+		next: next,
+		conf: conf,
+	}
+}
+
+func (c *idempotencyClient) Do(req *http.Request) (*http.Response, error) {
+	if !hasIdempotencyKey(req) {
+		if !c.conf.GenerateKeys || (req.Method != http.MethodPost && req.Method != http.MethodPatch) {
+			return c.next.Do(req)
+		}
+
+		req = req.Clone(req.Context())
+		req.Header.Set(HeaderIdempotencyKey, newIdempotencyKey())
+	}
+
+	if c.conf.Store == nil {
+		return c.next.Do(req)
+	}
+
+	key := req.Method + " " + req.URL.Host + req.URL.Path + " " + req.Header.Get(HeaderIdempotencyKey)
+
+	if entry, ok, err := c.conf.Store.Get(req.Context(), key); err == nil && ok {
+		resp := entry.response(req, time.Since(entry.StoredAt))
+		resp.Header.Del("Age")
+		resp.Header.Set(HeaderIdempotentReplayed, "true")
+
+		return resp, nil
+	}
+
+	resp, err := c.next.Do(req)
+	if err != nil || !isFinalOutcome(resp.StatusCode) {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	_ = c.conf.Store.Set(req.Context(), key, &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   time.Now(),
+	}, c.conf.TTL)
+
+	return resp, nil
+}
+
+// isFinalOutcome excludes answers after which the operation may still be
+// executed: server errors, timeouts, throttling, a concurrent request with
+// the key, early data rejected and auth failures fixed by a new credential.
+func isFinalOutcome(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout,
+		http.StatusConflict, http.StatusTooEarly, http.StatusTooManyRequests:
+		return false
+	}
+
+	return statusCode < http.StatusInternalServerError
+}
+
+// newIdempotencyKey returns a random UUID v4, the format partners expect.
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
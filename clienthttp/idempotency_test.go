@@ -0,0 +1,135 @@
+package clienthttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type idempotencyFixture struct {
+	server *httptest.Server
+	calls  *int32
+	mu     sync.Mutex
+	keys   []string
+}
+
+func setupIdempotencyFixture(t *testing.T, statuses ...int) *idempotencyFixture {
+	f := &idempotencyFixture{calls: new(int32)}
+
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := atomic.AddInt32(f.calls, 1)
+
+		f.mu.Lock()
+		f.keys = append(f.keys, r.Header.Get(HeaderIdempotencyKey))
+		f.mu.Unlock()
+
+		if int(call) <= len(statuses) {
+			w.WriteHeader(statuses[call-1])
+		}
+
+		fmt.Fprintf(w, `{"payment":%d}`, call)
+	}))
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+func TestIdempotency(t *testing.T) {
+	retryPolicy := WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	t.Run("should generate the key once per builder", func(t *testing.T) {
+		builder := NewRequest(http.MethodPost, "/payments").WithNewIdempotencyKey()
+
+		key := builder.Build().Header.Get(HeaderIdempotencyKey)
+
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, key)
+		assert.Equal(t, key, builder.Build().Header.Get(HeaderIdempotencyKey))
+		assert.NotEqual(t, key, NewRequest(http.MethodPost, "/payments").WithNewIdempotencyKey().Build().Header.Get(HeaderIdempotencyKey))
+	})
+
+	t.Run("should retry a POST carrying a key with the same key [RETRY]", func(t *testing.T) {
+		f := setupIdempotencyFixture(t, http.StatusServiceUnavailable)
+		client := NewClientHTTP(http.DefaultClient, f.server.URL, retryPolicy)
+
+		body, statusCode, err := client.Do(context.TODO(), NewRequest(http.MethodPost, "/payments").
+			WithIdempotencyKey("payment-42").
+			WithJSONBody(map[string]int{"amount": 10}).
+			Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, `{"payment":2}`, string(body))
+		assert.Equal(t, []string{"payment-42", "payment-42"}, f.keys)
+	})
+
+	t.Run("should not retry a POST without a key", func(t *testing.T) {
+		f := setupIdempotencyFixture(t, http.StatusServiceUnavailable)
+		client := NewClientHTTP(http.DefaultClient, f.server.URL, retryPolicy)
+
+		_, statusCode, err := client.Do(context.TODO(), NewRequest(http.MethodPost, "/payments").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(f.calls))
+	})
+
+	t.Run("should replay the stored response of a re-executed operation [STORE]", func(t *testing.T) {
+		f := setupIdempotencyFixture(t, http.StatusServiceUnavailable)
+		store := NewRedisCacheStore(newFakeRedis(), "idempotency:")
+
+		pay := func() *HandledResponse {
+			client := NewClientHTTP(http.DefaultClient, f.server.URL, retryPolicy, WithIdempotency(IdempotencyConfig{Store: store})).(HandlerClient)
+
+			handled, err := client.Handle(context.TODO(), NewRequest(http.MethodPost, "/payments").WithIdempotencyKey("payment-42").Build(),
+				NewResponseHandler().OnSuccess(nil))
+			assert.NoError(t, err)
+
+			return handled
+		}
+
+		first := pay()
+		replayed := pay()
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(f.calls))
+		assert.Empty(t, first.Header.Get(HeaderIdempotentReplayed))
+		assert.Equal(t, "true", replayed.Header.Get(HeaderIdempotentReplayed))
+		assert.Equal(t, http.StatusOK, replayed.StatusCode)
+	})
+
+	t.Run("should not store outcomes after which the operation may still run", func(t *testing.T) {
+		statuses := []int{http.StatusServiceUnavailable, http.StatusConflict, http.StatusRequestTimeout,
+			http.StatusTooEarly, http.StatusUnauthorized, http.StatusForbidden}
+
+		f := setupIdempotencyFixture(t, statuses...)
+		client := NewClientHTTP(http.DefaultClient, f.server.URL, WithIdempotency(IdempotencyConfig{Store: NewLRUCacheStore(10)}))
+
+		for _, expected := range append(statuses, http.StatusOK, http.StatusOK) {
+			_, statusCode, err := client.Do(context.TODO(), NewRequest(http.MethodPost, "/payments").WithIdempotencyKey("payment-42").Build())
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, statusCode)
+		}
+
+		assert.Equal(t, int32(len(statuses)+1), atomic.LoadInt32(f.calls))
+	})
+
+	t.Run("should generate keys for unsafe requests without one [GENERATE]", func(t *testing.T) {
+		f := setupIdempotencyFixture(t, http.StatusBadGateway)
+		client := NewClientHTTP(http.DefaultClient, f.server.URL, retryPolicy, WithIdempotency(IdempotencyConfig{GenerateKeys: true}))
+
+		_, statusCode, err := client.Do(context.TODO(), NewRequest(http.MethodPost, "/payments").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Len(t, f.keys, 2)
+		assert.NotEmpty(t, f.keys[0])
+		assert.Equal(t, f.keys[0], f.keys[1])
+	})
+}
@@ -0,0 +1,41 @@
+package clienthttp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/logger"
+)
+
+func WithLogger(log logger.Logger) Option {
+	return func(c *clientHttp) {
+		c.log = log
+	}
+}
+
+func loggingMiddleware(log logger.Logger) Middleware {
+	return func(next ClientAPI) ClientAPI {
+		return ClientAPIFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+
+			resp, err := next.Do(req)
+
+			fields := []logger.Field{
+				logger.Any("method", req.Method),
+				logger.Any("url", req.URL.Redacted()),
+				logger.Latency(time.Since(start)),
+			}
+
+			switch {
+			case err != nil:
+				log.Log(req.Context(), logger.LevelError, "http request [failed]", append(fields, logger.Err(err))...)
+			case resp.StatusCode >= http.StatusInternalServerError:
+				log.Log(req.Context(), logger.LevelWarn, "http request [server error]", append(fields, logger.Status(resp.StatusCode))...)
+			default:
+				log.Log(req.Context(), logger.LevelDebug, "http request [done]", append(fields, logger.Status(resp.StatusCode))...)
+			}
+
+			return resp, err
+		})
+	}
+}
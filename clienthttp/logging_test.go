@@ -0,0 +1,37 @@
+package clienthttp_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/dot-backend/synergetic-craft/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientHttp_WithLogger(t *testing.T) {
+	t.Run("should log each attempt with status and latency", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer ts.Close()
+
+		var buf bytes.Buffer
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithLogger(
+			logger.NewSlog(slog.New(slog.NewJSONHandler(&buf, nil))),
+		))
+
+		_, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/users").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, statusCode)
+		assert.Contains(t, buf.String(), `"level":"WARN","msg":"http request [server error]","method":"GET","url":"`+ts.URL+`/users"`)
+		assert.Contains(t, buf.String(), `"status":502`)
+		assert.Contains(t, buf.String(), `"latency":`)
+	})
+}
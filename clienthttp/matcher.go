@@ -0,0 +1,355 @@
+package clienthttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// RequestMatcher returns nil when req matches, or an error describing the
+// difference so MockClient can report why no expectation was met.
+type RequestMatcher func(req *http.Request) error
+
+func MatchAll(matchers ...RequestMatcher) RequestMatcher {
+	return func(req *http.Request) error {
+		for _, match := range matchers {
+			if err := match(req); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func MethodIs(method string) RequestMatcher {
+	return func(req *http.Request) error {
+		if req.Method != method {
+			return mismatch("Method doesn't match", method, req.Method)
+		}
+
+		return nil
+	}
+}
+
+func PathIs(expected string) RequestMatcher {
+	return func(req *http.Request) error {
+		if req.URL.Path != expected {
+			return mismatch("Path doesn't match", expected, req.URL.Path)
+		}
+
+		return nil
+	}
+}
+
+func PathMatchesRegex(pattern string) RequestMatcher {
+	re := regexp.MustCompile(pattern)
+
+	return func(req *http.Request) error {
+		if !re.MatchString(req.URL.Path) {
+			return mismatch("Path doesn't match regex", pattern, req.URL.Path)
+		}
+
+		return nil
+	}
+}
+
+// PathMatchesGlob uses path.Match syntax, so "*" never crosses a "/".
+func PathMatchesGlob(pattern string) RequestMatcher {
+	return func(req *http.Request) error {
+		if ok, err := path.Match(pattern, req.URL.Path); err != nil || !ok {
+			return mismatch("Path doesn't match glob", pattern, req.URL.Path)
+		}
+
+		return nil
+	}
+}
+
+// HasHeaders requires every expected header with the same values and ignores
+// any other header of the request.
+func HasHeaders(expected http.Header) RequestMatcher {
+	return func(req *http.Request) error {
+		return subsetValues("Headers don't match", expected, req.Header)
+	}
+}
+
+// HasQuery requires every expected query parameter with the same values and
+// ignores any other parameter of the request.
+func HasQuery(expected url.Values) RequestMatcher {
+	return func(req *http.Request) error {
+		return subsetValues("Query parameters don't match", expected, req.URL.Query())
+	}
+}
+
+// JSONBodyEquals compares bodies as decoded JSON, so key order and
+// whitespace do not matter.
+func JSONBodyEquals(expected interface{}) RequestMatcher {
+	return func(req *http.Request) error {
+		want, got, err := decodeJSONBodies(expected, req)
+		if err != nil {
+			return err
+		}
+
+		if !reflect.DeepEqual(want, got) {
+			return mismatch("JSON body doesn't match", indentJSON(want), indentJSON(got))
+		}
+
+		return nil
+	}
+}
+
+// JSONBodyContains requires the fields of expected to be present in the body
+// with the same values; arrays are compared element by element.
+func JSONBodyContains(expected interface{}) RequestMatcher {
+	return func(req *http.Request) error {
+		want, got, err := decodeJSONBodies(expected, req)
+		if err != nil {
+			return err
+		}
+
+		if !isJSONSubset(want, got) {
+			return mismatch("JSON body doesn't contain the expected fields", indentJSON(want), indentJSON(got))
+		}
+
+		return nil
+	}
+}
+
+// JSONPathEquals checks a single value addressed as "$.user.roles[0]"; the
+// leading "$." is optional.
+func JSONPathEquals(jsonPath string, expected interface{}) RequestMatcher {
+	return func(req *http.Request) error {
+		want, got, err := decodeJSONBodies(expected, req)
+		if err != nil {
+			return err
+		}
+
+		value, ok := lookupJSONPath(got, jsonPath)
+		if !ok {
+			return mismatch(fmt.Sprintf("JSON path %s not found", jsonPath), indentJSON(want), indentJSON(got))
+		}
+
+		if !reflect.DeepEqual(want, value) {
+			return mismatch(fmt.Sprintf("JSON path %s doesn't match", jsonPath), indentJSON(want), indentJSON(value))
+		}
+
+		return nil
+	}
+}
+
+func RequestPredicate(description string, fn func(req *http.Request) bool) RequestMatcher {
+	return func(req *http.Request) error {
+		if !fn(req) {
+			return fmt.Errorf("Predicate doesn't match: %s", description)
+		}
+
+		return nil
+	}
+}
+
+func mismatch(msg, expected, actual string) error {
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected + "\n"),
+		B:        difflib.SplitLines(actual + "\n"),
+		FromFile: "Expected",
+		ToFile:   "Actual",
+		Context:  2,
+	})
+
+	return fmt.Errorf("%s:\n%s", msg, diff)
+}
+
+func subsetValues(msg string, expected, actual map[string][]string) error {
+	for _, key := range sortedKeys(expected) {
+		values, ok := actual[key]
+		if !ok || !reflect.DeepEqual(expected[key], values) {
+			return mismatch(msg, msgValues(key, expected[key]), msgValues(key, values))
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(values ...map[string][]string) []string {
+	seen := make(map[string]bool)
+
+	var keys []string
+
+	for _, v := range values {
+		for key := range v {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func msgValues(key string, values []string) string {
+	return msg(key, strings.Join(values, ", "))
+}
+
+func decodeJSONBodies(expected interface{}, req *http.Request) (interface{}, interface{}, error) {
+	want, err := normalizeJSON(expected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := peekBody(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var got interface{}
+	if err := json.Unmarshal(body, &got); err != nil {
+		return nil, nil, mismatch("Body is not JSON", indentJSON(want), string(body))
+	}
+
+	return want, got, nil
+}
+
+// normalizeJSON round trips v so structs, maps and raw JSON compare as the
+// same generic values json.Unmarshal produces.
+func normalizeJSON(v interface{}) (interface{}, error) {
+	var data []byte
+
+	switch value := v.(type) {
+	case []byte:
+		data = value
+	case json.RawMessage:
+		data = value
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+func indentJSON(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
+
+func isJSONSubset(expected, actual interface{}) bool {
+	switch want := expected.(type) {
+	case map[string]interface{}:
+		got, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		for key, value := range want {
+			if field, ok := got[key]; !ok || !isJSONSubset(value, field) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		got, ok := actual.([]interface{})
+		if !ok || len(got) != len(want) {
+			return false
+		}
+
+		for i := range want {
+			if !isJSONSubset(want[i], got[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return reflect.DeepEqual(expected, actual)
+	}
+}
+
+func lookupJSONPath(doc interface{}, jsonPath string) (interface{}, bool) {
+	segments, err := parseJSONPath(jsonPath)
+	if err != nil {
+		return nil, false
+	}
+
+	current := doc
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+
+			current = value
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+var errInvalidJSONPath = errors.New("invalid json path")
+
+func parseJSONPath(jsonPath string) ([]string, error) {
+	jsonPath = strings.TrimPrefix(strings.TrimPrefix(jsonPath, "$"), ".")
+
+	var segments []string
+
+	for _, part := range strings.Split(jsonPath, ".") {
+		name, indexes, _ := strings.Cut(part, "[")
+		if name != "" {
+			segments = append(segments, name)
+		}
+
+		if indexes == "" {
+			if name == "" {
+				return nil, errInvalidJSONPath
+			}
+
+			continue
+		}
+
+		for _, index := range strings.Split(indexes, "[") {
+			index, ok := strings.CutSuffix(index, "]")
+			if !ok || index == "" {
+				return nil, errInvalidJSONPath
+			}
+
+			segments = append(segments, index)
+		}
+	}
+
+	return segments, nil
+}
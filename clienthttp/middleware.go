@@ -0,0 +1,100 @@
+package clienthttp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+const HeaderRequestID = "X-Request-Id"
+
+type ClientAPIFunc func(req *http.Request) (*http.Response, error)
+
+func (f ClientAPIFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type Middleware func(next ClientAPI) ClientAPI
+
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *clientHttp) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+func chain(next ClientAPI, middlewares []Middleware) ClientAPI {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i](next)
+	}
+
+	return next
+}
+
+func HeaderMiddleware(key, value string) Middleware {
+	return func(next ClientAPI) ClientAPI {
+		return ClientAPIFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set(key, value)
+
+			return next.Do(req)
+		})
+	}
+}
+
+type requestIDKey struct{}
+
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok && requestID != ""
+}
+
+func RequestIDMiddleware(header string) Middleware {
+	if header == "" {
+		header = HeaderRequestID
+	}
+
+	return func(next ClientAPI) ClientAPI {
+		return ClientAPIFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(header) != "" {
+				return next.Do(req)
+			}
+
+			requestID, ok := RequestIDFromContext(req.Context())
+			if !ok {
+				requestID = newRequestID()
+			}
+
+			req = req.Clone(req.Context())
+			req.Header.Set(header, requestID)
+
+			return next.Do(req)
+		})
+	}
+}
+
+func ObserveMiddleware(observe func(req *http.Request, resp *http.Response, err error, latency time.Duration)) Middleware {
+	return func(next ClientAPI) ClientAPI {
+		return ClientAPIFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+
+			resp, err := next.Do(req)
+
+			observe(req, resp, err, time.Since(start))
+
+			return resp, err
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
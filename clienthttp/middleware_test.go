@@ -0,0 +1,94 @@
+package clienthttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientHttp_Middleware(t *testing.T) {
+	t.Run("should run requests and responses through the chain in order [ORDER]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+
+		var calls []string
+
+		trace := func(name string) clienthttp.Middleware {
+			return func(next clienthttp.ClientAPI) clienthttp.ClientAPI {
+				return clienthttp.ClientAPIFunc(func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, "request "+name)
+
+					resp, err := next.Do(req)
+
+					calls = append(calls, "response "+name)
+
+					return resp, err
+				})
+			}
+		}
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL,
+			clienthttp.WithMiddleware(trace("first"), trace("second")),
+			clienthttp.WithMiddleware(clienthttp.HeaderMiddleware("Authorization", "Bearer token")),
+		)
+
+		_, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, statusCode)
+		assert.Equal(t, []string{"request first", "request second", "response second", "response first"}, calls)
+	})
+
+	t.Run("should propagate the request id from the context [REQUEST ID]", func(t *testing.T) {
+		received := make(chan string, 2)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received <- r.Header.Get(clienthttp.HeaderRequestID)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithMiddleware(clienthttp.RequestIDMiddleware("")))
+
+		ctx := clienthttp.ContextWithRequestID(context.TODO(), "req-123")
+
+		_, _, err := client.Do(ctx, clienthttp.NewRequest(http.MethodGet, "/unit-test").Build())
+		assert.NoError(t, err)
+		assert.Equal(t, "req-123", <-received)
+
+		_, _, err = client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test").Build())
+		assert.NoError(t, err)
+		assert.Len(t, <-received, 32)
+	})
+
+	t.Run("should observe every attempt [OBSERVE]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		var statuses []int
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL,
+			clienthttp.WithRetryPolicy(clienthttp.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+			clienthttp.WithMiddleware(clienthttp.ObserveMiddleware(func(req *http.Request, resp *http.Response, err error, latency time.Duration) {
+				assert.NoError(t, err)
+				assert.Greater(t, latency, time.Duration(0))
+
+				statuses = append(statuses, resp.StatusCode)
+			})),
+		)
+
+		_, _, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}, statuses)
+	})
+}
@@ -3,38 +3,195 @@ package clienthttp
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/stretchr/testify/assert"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+var ErrUnmatchedRequest = errors.New("mock client [unmatched request]")
+
 type MockClientAPI struct {
 	mock.Mock
+
+	mu        sync.Mutex
+	failure   string
+	matches   map[*http.Request]*requestMatches
+	unmatched []string
 }
 
-func (m *MockClientAPI) Do(req *http.Request) (*http.Response, error) {
+type requestMatches struct {
+	accepted   bool
+	mismatches []string
+}
+
+// Do answers with the first expectation accepting req. Once Test was called,
+// a request no expectation accepts is returned as ErrUnmatchedRequest and kept
+// for Unmatched instead of aborting the calling goroutine.
+func (m *MockClientAPI) Do(req *http.Request) (resp *http.Response, err error) {
+	matched := false
+	defer func() {
+		matches := m.takeMatches(req)
+		if matched {
+			return
+		}
+
+		r := recover()
+
+		failure, ok := r.(mockFailure)
+		if !ok {
+			if r != nil {
+				panic(r)
+			}
+
+			return
+		}
+
+		// A request some matcher accepted was rejected by testify itself, for
+		// being out of order or beyond the expected count.
+		reason := strings.TrimSpace(string(failure))
+		if !matches.accepted && len(matches.mismatches) > 0 {
+			reason = strings.Join(matches.mismatches, "\n")
+		}
+
+		m.mu.Lock()
+		m.unmatched = append(m.unmatched, fmt.Sprintf("%s %s\n%s", req.Method, req.URL, reason))
+		m.mu.Unlock()
+
+		resp, err = nil, fmt.Errorf("%w: %s %s", ErrUnmatchedRequest, req.Method, req.URL)
+	}()
+
 	args := m.Called(req)
+	matched = true
+
+	if response, ok := args.Get(0).(MockResponse); ok {
+		return response.respond(req)
+	}
+
 	return args.Get(0).(*http.Response), args.Error(1)
 }
 
+// Test routes testify failures of Do to ErrUnmatchedRequest.
+func (m *MockClientAPI) Test(t mock.TestingT) {
+	m.Mock.Test(failureRecorder{api: m, TestingT: t})
+}
+
+func (m *MockClientAPI) Unmatched() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]string(nil), m.unmatched...)
+}
+
+// match runs matcher as an argument matcher of Do, keeping the mismatch so it
+// can be reported if no other expectation accepts the request.
+func (m *MockClientAPI) match(req *http.Request, matcher RequestMatcher) bool {
+	err := matcher(req)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.matches == nil {
+		m.matches = make(map[*http.Request]*requestMatches)
+	}
+
+	matches, ok := m.matches[req]
+	if !ok {
+		matches = &requestMatches{}
+		m.matches[req] = matches
+	}
+
+	if err == nil {
+		matches.accepted = true
+		return true
+	}
+
+	for _, reported := range matches.mismatches {
+		if reported == err.Error() {
+			return false
+		}
+	}
+
+	matches.mismatches = append(matches.mismatches, err.Error())
+
+	return false
+}
+
+func (m *MockClientAPI) takeMatches(req *http.Request) requestMatches {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	matches, ok := m.matches[req]
+	if !ok {
+		return requestMatches{}
+	}
+
+	delete(m.matches, req)
+
+	return *matches
+}
+
+type mockFailure string
+
+// failureRecorder turns the Errorf/FailNow pair testify issues for an
+// unexpected call into a mockFailure panic that Do recovers. testify holds its
+// own lock across both calls, so failures never interleave.
+type failureRecorder struct {
+	mock.TestingT
+	api *MockClientAPI
+}
+
+func (f failureRecorder) Errorf(format string, args ...interface{}) {
+	f.api.mu.Lock()
+	defer f.api.mu.Unlock()
+
+	f.api.failure = fmt.Sprintf(format, args...)
+}
+
+func (f failureRecorder) FailNow() {
+	f.api.mu.Lock()
+	defer f.api.mu.Unlock()
+
+	panic(mockFailure(f.api.failure))
+}
+
 type MockClient struct {
 	mock *MockClientAPI
 	ClientHTTP
-	testing *testing.T
+	testing  testing.TB
+	asserted bool
 }
 
-func NewMockClient(t *testing.T) *MockClient {
+// NewMockClient fails t on cleanup when requests went unmatched and
+// AssertExpectations was never called to report them. Options configure the
+// client in front of the mock, e.g. to exercise a retry policy.
+func NewMockClient(t testing.TB, opts ...Option) *MockClient {
 	mockClientAPI := new(MockClientAPI)
+	mockClientAPI.Test(t)
 
-	return &MockClient{
+	m := &MockClient{
 		mock:       mockClientAPI,
-		ClientHTTP: NewClientHTTP(mockClientAPI, ""),
+		ClientHTTP: NewClientHTTP(mockClientAPI, "", opts...),
 		testing:    t,
 	}
+
+	t.Cleanup(func() {
+		if !m.asserted {
+			m.assertMatched()
+		}
+	})
+
+	return m
 }
 
 func (m *MockClient) Do(ctx context.Context, req *http.Request) (response []byte, statusCode int, err error) {
@@ -45,49 +202,100 @@ func (m *MockClient) DoWithTimeout(ctx context.Context, req *http.Request, timeo
 	return m.ClientHTTP.DoWithTimeout(ctx, req, timeout, expectedCode, out)
 }
 
+func (m *MockClient) Handle(ctx context.Context, req *http.Request, handler ResponseHandler) (*HandledResponse, error) {
+	return m.ClientHTTP.(HandlerClient).Handle(ctx, req, handler)
+}
+
+func (m *MockClient) DoStream(ctx context.Context, req *http.Request) (*StreamResponse, error) {
+	return m.ClientHTTP.(StreamClient).DoStream(ctx, req)
+}
+
 func (m *MockClient) ExpectedRequest(expectedReq *http.Request, response []byte, statusCode int, APIErr error) *mock.Call {
-	var (
-		expected, actual string
-		isMatch          bool
-	)
+	return m.ExpectedRequestMatching(response, statusCode, APIErr, m.sameRequest(expectedReq))
+}
+
+// ExpectedRequestMatching expects a request accepted by every matcher.
+func (m *MockClient) ExpectedRequestMatching(response []byte, statusCode int, APIErr error, matchers ...RequestMatcher) *mock.Call {
+	return m.on(MatchAll(matchers...)).Return(MockResponse{StatusCode: statusCode, Body: response, Err: APIErr})
+}
 
+func (m *MockClient) on(matcher RequestMatcher) *mock.Call {
 	return m.mock.On("Do", mock.MatchedBy(func(req *http.Request) bool {
-		isMatch = checkMethod(expectedReq, req) && checkURL(expectedReq, req)
-		if !isMatch {
-			m.logger("Method/Url don´t match",
+		return m.mock.match(req, matcher)
+	}))
+}
+
+func (m *MockClient) sameRequest(expectedReq *http.Request) RequestMatcher {
+	return func(req *http.Request) error {
+		if !checkMethod(expectedReq, req) || !checkURL(expectedReq, req) {
+			return mismatch("Method/Url don't match",
 				fmt.Sprintf("%s %s", expectedReq.Method, expectedReq.URL),
 				fmt.Sprintf("%s %s", req.Method, req.URL))
-			return false
 		}
 
-		expected, actual, isMatch = checkHeaders(expectedReq, req)
-		if !isMatch {
-			m.logger("Headers don´t match", expected, actual)
-			return false
+		if expected, actual, isMatch := checkHeaders(expectedReq, req); !isMatch {
+			return mismatch("Headers don't match", expected, actual)
 		}
 
-		expected, actual, isMatch = checkQueryParams(expectedReq, req)
-		if !isMatch {
-			m.logger("Query Parameters don´t match", expected, actual)
-			return false
+		if expected, actual, isMatch := checkQueryParams(expectedReq, req); !isMatch {
+			return mismatch("Query Parameters don't match", expected, actual)
 		}
 
-		expected, actual, isMatch = m.checkBody(expectedReq, req)
-		if !isMatch {
-			m.logger("Body don´t match", expected, actual)
-			return false
+		if expected, actual, isMatch := m.checkBody(expectedReq, req); !isMatch {
+			return mismatch("Body doesn't match", expected, actual)
 		}
 
-		return true
-	})).Return(&http.Response{
-		StatusCode: statusCode,
-		Body:       io.NopCloser(bytes.NewBuffer(response))},
-		APIErr,
-	)
+		return nil
+	}
+}
+
+// ReplayCassette expects every interaction of the cassette once, in the order
+// they were recorded when several of them match the same request.
+func (m *MockClient) ReplayCassette(path string, conf CassetteConfig) error {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return err
+	}
+
+	matchers := conf.matchers()
+
+	for _, interaction := range cassette.Interactions {
+		recorded := interaction.Request
+
+		body, err := interaction.Response.BodyBytes()
+		if err != nil {
+			return err
+		}
+
+		m.mock.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			for _, match := range matchers {
+				if !match(recorded, req) {
+					return false
+				}
+			}
+
+			return true
+		})).Return(MockResponse{
+			StatusCode: interaction.Response.StatusCode,
+			Header:     interaction.Response.Header,
+			Body:       body,
+		}).Once()
+	}
+
+	return nil
 }
 
 func (m *MockClient) AssertExpectations() {
+	m.asserted = true
+
 	m.mock.AssertExpectations(m.testing)
+	m.assertMatched()
+}
+
+func (m *MockClient) assertMatched() {
+	if unmatched := m.mock.Unmatched(); len(unmatched) > 0 {
+		m.testing.Errorf("%d unmatched requests:\n\n%s", len(unmatched), strings.Join(unmatched, "\n\n"))
+	}
 }
 
 func checkMethod(expected, req *http.Request) bool {
@@ -109,7 +317,11 @@ func checkHeaders(expected, req *http.Request) (string, string, bool) {
 			false
 	}
 
-	for key := range req.Header {
+	for _, key := range sortedKeys(expected.Header, req.Header) {
+		if key == "Content-Type" && isMultipart(expected) && isMultipart(req) {
+			continue
+		}
+
 		expectedHeader = expected.Header.Get(key)
 		actualHeader = req.Header.Get(key)
 
@@ -131,7 +343,7 @@ func checkQueryParams(expected, req *http.Request) (string, string, bool) {
 			false
 	}
 
-	for key := range req.URL.Query() {
+	for _, key := range sortedKeys(expected.URL.Query(), req.URL.Query()) {
 		expectedParam = expected.URL.Query().Get(key)
 		actualParam = req.URL.Query().Get(key)
 
@@ -147,6 +359,10 @@ func (m *MockClient) checkBody(expected, req *http.Request) (string, string, boo
 	expectedBody := m.readRequestBody(expected)
 	actualBody := m.readRequestBody(req)
 
+	if isMultipart(expected) {
+		return checkMultipartBody(expected, expectedBody, req, actualBody)
+	}
+
 	if len(expectedBody) < 1 && len(actualBody) < 1 {
 		return string(expectedBody), string(actualBody), true
 	}
@@ -159,6 +375,10 @@ func (m *MockClient) checkBody(expected, req *http.Request) (string, string, boo
 		return string(expectedBody), string(actualBody), false
 	}
 
+	if json.Valid(expectedBody) && json.Valid(actualBody) {
+		return checkJSONBody(expectedBody, actualBody)
+	}
+
 	if !assert.ObjectsAreEqual(string(expectedBody), string(actualBody)) {
 		return string(expectedBody), string(actualBody), false
 	}
@@ -166,6 +386,92 @@ func (m *MockClient) checkBody(expected, req *http.Request) (string, string, boo
 	return "", "", true
 }
 
+func checkJSONBody(expectedBody, actualBody []byte) (string, string, bool) {
+	var expected, actual interface{}
+
+	_ = json.Unmarshal(expectedBody, &expected)
+	_ = json.Unmarshal(actualBody, &actual)
+
+	if !reflect.DeepEqual(expected, actual) {
+		return indentJSON(expected), indentJSON(actual), false
+	}
+
+	return "", "", true
+}
+
+func checkMultipartBody(expected *http.Request, expectedBody []byte, req *http.Request, actualBody []byte) (string, string, bool) {
+	expectedParts, err := readMultipartParts(expected.Header.Get("Content-Type"), expectedBody)
+	if err != nil {
+		return "Expected multipart body: " + err.Error(), string(actualBody), false
+	}
+
+	actualParts, err := readMultipartParts(req.Header.Get("Content-Type"), actualBody)
+	if err != nil {
+		return fmt.Sprint(expectedParts), "Invalid multipart body: " + err.Error(), false
+	}
+
+	if len(expectedParts) != len(actualParts) {
+		return fmt.Sprintf("%d parts %v", len(expectedParts), expectedParts),
+			fmt.Sprintf("%d parts %v", len(actualParts), actualParts),
+			false
+	}
+
+	for i := range expectedParts {
+		if expectedParts[i] != actualParts[i] {
+			return expectedParts[i].String(), actualParts[i].String(), false
+		}
+	}
+
+	return "", "", true
+}
+
+type multipartPart struct {
+	name     string
+	filename string
+	content  string
+}
+
+func (p multipartPart) String() string {
+	if p.filename != "" {
+		return fmt.Sprintf("part: \"%s\" file: \"%s\" content: \"%s\"", p.name, p.filename, p.content)
+	}
+
+	return fmt.Sprintf("part: \"%s\" value: \"%s\"", p.name, p.content)
+}
+
+func readMultipartParts(contentType string, body []byte) ([]multipartPart, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+
+	var parts []multipartPart
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, multipartPart{
+			name:     part.FormName(),
+			filename: part.FileName(),
+			content:  string(content),
+		})
+	}
+}
+
 func (m *MockClient) readRequestBody(req *http.Request) []byte {
 	if req.Body == nil {
 		return []byte{}
@@ -181,12 +487,6 @@ func (m *MockClient) readRequestBody(req *http.Request) []byte {
 	return body
 }
 
-func (m *MockClient) logger(msg string, expected, actual string) {
-	m.testing.Fatal(
-		fmt.Sprintf("%s: \nExpected: %s\nActual: %s", msg, expected, actual),
-	)
-}
-
 func msg(key, value string) string {
 	return fmt.Sprintf("key: \"%s\" value: \"%s\"", key, value)
 }
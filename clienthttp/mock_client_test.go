@@ -1,8 +1,14 @@
 package clienthttp
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMockClient_ExpectedRequest(t *testing.T) {
@@ -34,3 +40,123 @@ func setupTestMockClientFixture(t *testing.T) *testMockClientFixture {
 		resource:   NewTestClient(mockClient),
 	}
 }
+
+func TestMockClient_MultipartBody(t *testing.T) {
+	newMultipartRequest := func(content string) *http.Request {
+		return NewRequest(http.MethodPost, "/documents").
+			WithMultipartField("customer", "diego").
+			WithMultipartFile("document", "id.pdf", strings.NewReader(content)).
+			Build()
+	}
+
+	t.Run("should match multipart requests part by part regardless of boundary", func(t *testing.T) {
+		mockClient := NewMockClient(t)
+		mockClient.ExpectedRequest(newMultipartRequest("content"), []byte(``), http.StatusCreated, nil)
+
+		_, statusCode, err := mockClient.Do(context.Background(), newMultipartRequest("content"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, statusCode)
+		mockClient.AssertExpectations()
+	})
+
+	t.Run("should report the part that does not match", func(t *testing.T) {
+		f := setupTestMockClientFixture(t)
+
+		expected, actual := newMultipartRequest("content"), newMultipartRequest("other content")
+
+		expectedMsg, actualMsg, isMatch := f.mockClient.checkBody(expected, actual)
+
+		assert.False(t, isMatch)
+		assert.Equal(t, `part: "document" file: "id.pdf" content: "content"`, expectedMsg)
+		assert.Equal(t, `part: "document" file: "id.pdf" content: "other content"`, actualMsg)
+	})
+}
+
+func TestMockClient_Matchers(t *testing.T) {
+	t.Run("should compare JSON bodies semantically [JSON]", func(t *testing.T) {
+		mockClient := NewMockClient(t)
+		mockClient.ExpectedRequest(NewRequest(http.MethodPost, "/users").WithBodyBytes([]byte(`{"name":"diego","age":30}`)).Build(),
+			[]byte(``), http.StatusCreated, nil)
+
+		_, statusCode, err := mockClient.Do(context.Background(),
+			NewRequest(http.MethodPost, "/users").WithBodyBytes([]byte(`{ "age": 30, "name": "diego" }`)).Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, statusCode)
+		mockClient.AssertExpectations()
+	})
+
+	t.Run("should not match when an expected header is missing [HEADERS]", func(t *testing.T) {
+		expected := NewRequest(http.MethodGet, "/users").WithHeader("X-Tenant", "acme").WithHeader("X-Trace", "1").Build()
+		actual := NewRequest(http.MethodGet, "/users").WithHeader("X-Trace", "1").Build()
+
+		expectedMsg, actualMsg, isMatch := checkHeaders(expected, actual)
+
+		assert.False(t, isMatch)
+		assert.Equal(t, `key: "X-Tenant" value: "acme"`, expectedMsg)
+		assert.Equal(t, `key: "X-Tenant" value: ""`, actualMsg)
+	})
+
+	t.Run("should compose matchers [COMPOSE]", func(t *testing.T) {
+		mockClient := NewMockClient(t)
+		mockClient.ExpectedRequestMatching([]byte(`{"id":7}`), http.StatusCreated, nil,
+			MethodIs(http.MethodPost),
+			PathMatchesRegex(`^/tenants/[a-z]+/users$`),
+			HasHeaders(http.Header{"X-Tenant": {"acme"}}),
+			HasQuery(url.Values{"notify": {"true"}}),
+			JSONBodyContains(map[string]interface{}{"user": map[string]interface{}{"name": "diego"}}),
+			JSONPathEquals("$.user.roles[1]", "admin"),
+			RequestPredicate("has a request id", func(req *http.Request) bool {
+				return req.Header.Get("X-Request-Id") != ""
+			}),
+		)
+
+		body, statusCode, err := mockClient.Do(context.Background(), NewRequest(http.MethodPost, "/tenants/acme/users").
+			WithHeader("X-Tenant", "acme").
+			WithHeader("X-Request-Id", "abc").
+			WithQueryParam("notify", "true").
+			WithQueryParam("source", "web").
+			WithBodyBytes([]byte(`{"user":{"name":"diego","roles":["user","admin"]},"active":true}`)).
+			Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, statusCode)
+		assert.Equal(t, `{"id":7}`, string(body))
+		mockClient.AssertExpectations()
+	})
+
+	t.Run("should match paths with globs", func(t *testing.T) {
+		req := NewRequest(http.MethodGet, "/users/42/orders").Build()
+
+		assert.NoError(t, PathMatchesGlob("/users/*/orders")(req))
+		assert.Error(t, PathMatchesGlob("/users/*")(req))
+	})
+
+	t.Run("should report a diff instead of failing inside the matcher [DIFF]", func(t *testing.T) {
+		recorder := &testingRecorder{TB: t}
+
+		mockClient := NewMockClient(recorder)
+		mockClient.ExpectedRequestMatching(nil, http.StatusOK, nil, PathIs("/users"), JSONBodyEquals(map[string]int{"id": 1})).Maybe()
+		mockClient.ExpectedRequestMatching(nil, http.StatusOK, nil, PathIs("/orders")).Maybe()
+
+		_, _, err := mockClient.Do(context.Background(), NewRequest(http.MethodPost, "/users").WithBodyBytes([]byte(`{"id":2}`)).Build())
+
+		assert.ErrorIs(t, err, ErrUnmatchedRequest)
+
+		mockClient.AssertExpectations()
+
+		assert.Contains(t, recorder.errors, "1 unmatched requests")
+		assert.Contains(t, recorder.errors, "-  \"id\": 1\n+  \"id\": 2")
+		assert.Contains(t, recorder.errors, "-/orders\n+/users")
+	})
+}
+
+type testingRecorder struct {
+	testing.TB
+	errors string
+}
+
+func (r *testingRecorder) Errorf(format string, args ...interface{}) {
+	r.errors += fmt.Sprintf(format, args...)
+}
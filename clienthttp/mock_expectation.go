@@ -0,0 +1,120 @@
+package clienthttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockResponse is what a MockClient answers for one matching call. Each call
+// gets a fresh body, so the same response can be served several times.
+type MockResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Err is returned as a transport error instead of a response.
+	Err error
+	// Latency delays the answer; the request context still cancels it.
+	Latency time.Duration
+}
+
+func (r MockResponse) respond(req *http.Request) (*http.Response, error) {
+	if r.Latency > 0 {
+		timer := time.NewTimer(r.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	statusCode := r.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(r.Body)),
+		Request:    req,
+	}, nil
+}
+
+// Expectation is a sequence of responses served, in order, to the requests
+// accepted by its matchers:
+//
+//	mockClient.Expect(MethodIs(http.MethodGet), PathIs("/users")).
+//		Respond(MockResponse{StatusCode: http.StatusServiceUnavailable}).Times(2).
+//		Respond(MockResponse{StatusCode: http.StatusOK, Body: body})
+type Expectation struct {
+	client  *MockClient
+	matcher RequestMatcher
+	calls   []*mock.Call
+}
+
+func (m *MockClient) Expect(matchers ...RequestMatcher) *Expectation {
+	return &Expectation{client: m, matcher: MatchAll(matchers...)}
+}
+
+// Respond appends a response answering the next matching call.
+func (e *Expectation) Respond(resp MockResponse) *Expectation {
+	e.calls = append(e.calls, e.client.on(e.matcher).Return(resp).Once())
+	return e
+}
+
+// Fail appends a transport error answering the next matching call.
+func (e *Expectation) Fail(err error) *Expectation {
+	return e.Respond(MockResponse{Err: err})
+}
+
+// Times serves the last response to exactly n calls. Without a previous
+// Respond it applies to an empty 200 response.
+func (e *Expectation) Times(n int) *Expectation {
+	e.last().Times(n)
+	return e
+}
+
+// Repeatedly serves the last response to every further matching call.
+func (e *Expectation) Repeatedly() *Expectation {
+	e.last().Times(0)
+	return e
+}
+
+func (e *Expectation) last() *mock.Call {
+	if len(e.calls) == 0 {
+		e.Respond(MockResponse{})
+	}
+
+	return e.calls[len(e.calls)-1]
+}
+
+// InOrder requires every expectation to be fulfilled before the next one
+// accepts a request; a request arriving early is reported as unmatched.
+func (m *MockClient) InOrder(expectations ...*Expectation) {
+	for i := 1; i < len(expectations); i++ {
+		expectations[i].first().NotBefore(expectations[i-1].last())
+	}
+}
+
+func (e *Expectation) first() *mock.Call {
+	if len(e.calls) == 0 {
+		e.Respond(MockResponse{})
+	}
+
+	return e.calls[0]
+}
@@ -0,0 +1,115 @@
+package clienthttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockClient_Expect(t *testing.T) {
+	getUsers := func() *http.Request {
+		return NewRequest(http.MethodGet, "/users").Build()
+	}
+
+	t.Run("should serve a response sequence through the retry policy [SEQUENCE]", func(t *testing.T) {
+		mockClient := NewMockClient(t, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+		mockClient.Expect(MethodIs(http.MethodGet), PathIs("/users")).
+			Respond(MockResponse{StatusCode: http.StatusServiceUnavailable}).Times(2).
+			Respond(MockResponse{StatusCode: http.StatusOK, Body: []byte(`[{"id":1}]`)})
+
+		body, statusCode, err := mockClient.Do(context.Background(), getUsers())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, `[{"id":1}]`, string(body))
+		mockClient.AssertExpectations()
+	})
+
+	t.Run("should fail when a call count is not met [TIMES]", func(t *testing.T) {
+		recorder := &testingRecorder{TB: t}
+
+		mockClient := NewMockClient(recorder)
+		mockClient.Expect(PathIs("/users")).Times(3)
+
+		for i := 0; i < 2; i++ {
+			_, _, err := mockClient.Do(context.Background(), getUsers())
+			assert.NoError(t, err)
+		}
+
+		mockClient.AssertExpectations()
+
+		assert.NotEmpty(t, recorder.errors)
+	})
+
+	t.Run("should report calls beyond the expected count as unmatched [EXTRA]", func(t *testing.T) {
+		recorder := &testingRecorder{TB: t}
+
+		mockClient := NewMockClient(recorder)
+		mockClient.Expect(PathIs("/users")).Respond(MockResponse{})
+
+		_, _, err := mockClient.Do(context.Background(), getUsers())
+		assert.NoError(t, err)
+
+		_, _, err = mockClient.Do(context.Background(), getUsers())
+		assert.ErrorIs(t, err, ErrUnmatchedRequest)
+
+		mockClient.AssertExpectations()
+
+		assert.Contains(t, recorder.errors, "1 unmatched requests")
+		assert.Contains(t, recorder.errors, "GET /users")
+	})
+
+	t.Run("should enforce the order of expectations [ORDER]", func(t *testing.T) {
+		recorder := &testingRecorder{TB: t}
+
+		mockClient := NewMockClient(recorder)
+		login := mockClient.Expect(PathIs("/login")).Respond(MockResponse{StatusCode: http.StatusNoContent})
+		users := mockClient.Expect(PathIs("/users")).Repeatedly()
+		mockClient.InOrder(login, users)
+
+		_, _, err := mockClient.Do(context.Background(), getUsers())
+		assert.ErrorIs(t, err, ErrUnmatchedRequest)
+
+		_, statusCode, err := mockClient.Do(context.Background(), NewRequest(http.MethodPost, "/login").Build())
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, statusCode)
+
+		for i := 0; i < 2; i++ {
+			_, _, err = mockClient.Do(context.Background(), getUsers())
+			assert.NoError(t, err)
+		}
+
+		mockClient.AssertExpectations()
+
+		assert.Contains(t, recorder.errors, "Must not be called before")
+	})
+
+	t.Run("should inject latency honouring the request context [LATENCY]", func(t *testing.T) {
+		mockClient := NewMockClient(t)
+		mockClient.Expect(PathIs("/users")).Respond(MockResponse{Latency: time.Second})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+
+		_, _, err := mockClient.Do(ctx, getUsers())
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("should inject transport errors [ERROR]", func(t *testing.T) {
+		mockClient := NewMockClient(t)
+		mockClient.Expect(PathIs("/users")).Fail(io.ErrUnexpectedEOF)
+
+		_, _, err := mockClient.Do(context.Background(), getUsers())
+
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		mockClient.AssertExpectations()
+	})
+}
@@ -0,0 +1,101 @@
+package clienthttp
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sync"
+)
+
+const multipartBodyKind = "multipart/form-data"
+
+type multipartEntry struct {
+	name     string
+	filename string
+	value    string
+	reader   io.Reader
+}
+
+func (h HTTPRequestBuilder) WithMultipartField(name, value string) HTTPRequestBuilder {
+	return h.withMultipart(multipartEntry{name: name, value: value})
+}
+
+func (h HTTPRequestBuilder) WithMultipartFile(name, filename string, reader io.Reader) HTTPRequestBuilder {
+	return h.withMultipart(multipartEntry{name: name, filename: filename, reader: reader})
+}
+
+func (h HTTPRequestBuilder) withMultipart(entry multipartEntry) HTTPRequestBuilder {
+	if h.bodyKind != "" && h.bodyKind != multipartBodyKind {
+		return h.withError(fmt.Errorf("request builder [ conflicting body options %s and %s ]", h.bodyKind, multipartBodyKind))
+	}
+
+	h.bodyKind = multipartBodyKind
+	h.multipart = append(h.multipart[:len(h.multipart):len(h.multipart)], entry)
+
+	return h
+}
+
+func (h HTTPRequestBuilder) multipartBody() (io.ReadCloser, string) {
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+
+	return &multipartStream{reader: reader, writer: writer, form: form, entries: h.multipart}, form.FormDataContentType()
+}
+
+// multipartStream starts writing the form on the first Read, so a request
+// that never reaches the transport doesn't leave the writer goroutine behind.
+type multipartStream struct {
+	once    sync.Once
+	reader  *io.PipeReader
+	writer  *io.PipeWriter
+	form    *multipart.Writer
+	entries []multipartEntry
+}
+
+func (s *multipartStream) Read(p []byte) (int, error) {
+	s.once.Do(func() {
+		go func() {
+			s.writer.CloseWithError(writeMultipart(s.form, s.entries))
+		}()
+	})
+
+	return s.reader.Read(p)
+}
+
+func (s *multipartStream) Close() error {
+	s.once.Do(func() {
+		_ = s.writer.Close()
+	})
+
+	return s.reader.Close()
+}
+
+func writeMultipart(form *multipart.Writer, entries []multipartEntry) error {
+	for _, entry := range entries {
+		if entry.reader == nil {
+			if err := form.WriteField(entry.name, entry.value); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		part, err := form.CreateFormFile(entry.name, entry.filename)
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.Copy(part, entry.reader); err != nil {
+			return err
+		}
+	}
+
+	return form.Close()
+}
+
+func isMultipart(req *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return mediaType == multipartBodyKind
+}
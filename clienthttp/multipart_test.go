@@ -0,0 +1,111 @@
+package clienthttp_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPRequestBuilder_Multipart(t *testing.T) {
+	t.Run("should stream fields and files as multipart/form-data [UPLOAD]", func(t *testing.T) {
+		document := strings.Repeat("kyc document ", 100000)
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, r.ParseMultipartForm(1<<20))
+			assert.Equal(t, "diego", r.FormValue("customer"))
+
+			file, header, err := r.FormFile("document")
+			assert.NoError(t, err)
+
+			defer file.Close()
+
+			content, _ := io.ReadAll(file)
+
+			assert.Equal(t, "id.pdf", header.Filename)
+			assert.Equal(t, document, string(content))
+
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer ts.Close()
+
+		req := clienthttp.NewRequest(http.MethodPost, "/documents").
+			WithMultipartField("customer", "diego").
+			WithMultipartFile("document", "id.pdf", strings.NewReader(document)).
+			Build()
+
+		assert.True(t, strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data; boundary="))
+		assert.Nil(t, req.GetBody)
+
+		_, statusCode, err := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).Do(context.TODO(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, statusCode)
+	})
+
+	t.Run("should return error when multipart conflicts with another body [CONFLICT]", func(t *testing.T) {
+		err := clienthttp.NewRequest(http.MethodPost, "/documents").
+			WithJSONBody(map[string]string{"name": "test"}).
+			WithMultipartField("customer", "diego").
+			Validate()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "conflicting body options application/json and multipart/form-data")
+	})
+	t.Run("should not start writing the form until the body is read [LAZY]", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+
+		for i := 0; i < 50; i++ {
+			clienthttp.NewRequest(http.MethodPost, "/documents").
+				WithMultipartFile("document", "id.pdf", strings.NewReader("kyc document")).
+				Build()
+		}
+
+		assert.Less(t, runtime.NumGoroutine(), before+50)
+	})
+
+	t.Run("should close the form when the request is rejected before sending [REJECTED]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		upload := func(client clienthttp.ClientHTTP) (*http.Request, error) {
+			req := clienthttp.NewRequest(http.MethodPost, "/documents").
+				WithMultipartFile("document", "id.pdf", strings.NewReader("kyc document")).
+				Build()
+
+			_, _, err := client.Do(context.TODO(), req)
+
+			return req, err
+		}
+
+		breaker := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithCircuitBreaker(clienthttp.CircuitBreakerConfig{
+			FailureRate: 0.5,
+			MinRequests: 1,
+			CoolDown:    time.Minute,
+		}))
+		limited := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithRateLimit(clienthttp.RateLimitConfig{
+			RateLimit: clienthttp.RateLimit{Requests: 1, Per: time.Minute},
+			Mode:      clienthttp.RateLimitFail,
+		}))
+
+		for _, client := range []clienthttp.ClientHTTP{breaker, limited} {
+			_, err := upload(client)
+			assert.NoError(t, err)
+
+			req, err := upload(client)
+			assert.Error(t, err)
+
+			_, err = req.Body.Read(make([]byte, 1))
+			assert.ErrorIs(t, err, io.ErrClosedPipe)
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package clienthttp
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Token struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresIn   int64     `json:"expires_in"`
+	Expiry      time.Time `json:"-"`
+}
+
+func (t *Token) valid(now time.Time, expiryDelta time.Duration) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+
+	return t.Expiry.IsZero() || now.Add(expiryDelta).Before(t.Expiry)
+}
+
+func (t *Token) authorization() string {
+	if t.TokenType == "" || strings.EqualFold(t.TokenType, "bearer") {
+		return "Bearer " + t.AccessToken
+	}
+
+	return t.TokenType + " " + t.AccessToken
+}
+
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+	Invalidate(token *Token)
+}
+
+type ClientCredentialsConfig struct {
+	TokenURL       string
+	ClientID       string
+	ClientSecret   string
+	Scopes         []string
+	EndpointParams url.Values
+	AuthInBody     bool
+	ExpiryDelta    time.Duration
+	Timeout        time.Duration
+	Client         ClientAPI
+}
+
+type clientCredentials struct {
+	conf   ClientCredentialsConfig
+	client HandlerClient
+	now    func() time.Time
+
+	mu       sync.Mutex
+	token    *Token
+	inflight *tokenCall
+}
+
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+func NewClientCredentials(conf ClientCredentialsConfig) TokenSource {
+	if conf.ExpiryDelta <= 0 {
+		conf.ExpiryDelta = 30 * time.Second
+	}
+
+	if conf.Timeout <= 0 {
+		conf.Timeout = 10 * time.Second
+	}
+
+	if conf.Client == nil {
+		conf.Client = http.DefaultClient
+	}
+
+	return &clientCredentials{
+		conf:   conf,
+		client: newClientHTTP(conf.Client, ""),
+		now:    time.Now,
+	}
+}
+
+func (c *clientCredentials) Token(ctx context.Context) (*Token, error) {
+	c.mu.Lock()
+
+	if c.token.valid(c.now(), c.conf.ExpiryDelta) {
+		token := c.token
+		c.mu.Unlock()
+
+		return token, nil
+	}
+
+	call := c.inflight
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		c.inflight = call
+
+		go c.refresh(call)
+	}
+
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *clientCredentials) Invalidate(token *Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == token {
+		c.token = nil
+	}
+}
+
+func (c *clientCredentials) refresh(call *tokenCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.conf.Timeout)
+	defer cancel()
+
+	call.token, call.err = c.fetch(ctx)
+
+	c.mu.Lock()
+	if call.err == nil {
+		c.token = call.token
+	}
+	c.inflight = nil
+	c.mu.Unlock()
+
+	close(call.done)
+}
+
+func (c *clientCredentials) fetch(ctx context.Context) (*Token, error) {
+	form := url.Values{}
+	for key, values := range c.conf.EndpointParams {
+		form[key] = values
+	}
+
+	form.Set("grant_type", "client_credentials")
+
+	if len(c.conf.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.conf.Scopes, " "))
+	}
+
+	if c.conf.AuthInBody {
+		form.Set("client_id", c.conf.ClientID)
+		form.Set("client_secret", c.conf.ClientSecret)
+	}
+
+	req, err := NewRequest(http.MethodPost, c.conf.TokenURL).
+		WithHeader("Accept", ContentTypeJSON).
+		WithFormBody(form).
+		BuildE()
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.conf.AuthInBody {
+		req.SetBasicAuth(url.QueryEscape(c.conf.ClientID), url.QueryEscape(c.conf.ClientSecret))
+	}
+
+	var token Token
+
+	_, err = c.client.Handle(ctx, req, NewResponseHandler().OnSuccess(&token))
+	if err != nil {
+		return nil, err
+	}
+
+	if token.ExpiresIn > 0 {
+		token.Expiry = c.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return &token, nil
+}
+
+func WithAuth(source TokenSource) Option {
+	return func(c *clientHttp) {
+		c.auth = source
+	}
+}
+
+func authMiddleware(source TokenSource) Middleware {
+	return func(next ClientAPI) ClientAPI {
+		return ClientAPIFunc(func(req *http.Request) (*http.Response, error) {
+			token, err := source.Token(req.Context())
+			if err != nil {
+				return nil, err
+			}
+
+			authReq := req.Clone(req.Context())
+			authReq.Header.Set("Authorization", token.authorization())
+
+			resp, err := next.Do(authReq)
+			if err != nil || resp.StatusCode != http.StatusUnauthorized || !isReplayable(req) {
+				return resp, err
+			}
+
+			source.Invalidate(token)
+
+			fresh, errToken := source.Token(req.Context())
+			if errToken != nil {
+				return resp, nil
+			}
+
+			retryReq, errReplay := replayRequest(req)
+			if errReplay != nil {
+				return resp, nil
+			}
+
+			discardResponse(resp)
+
+			retryReq.Header.Set("Authorization", fresh.authorization())
+
+			return next.Do(retryReq)
+		})
+	}
+}
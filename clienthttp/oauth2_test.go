@@ -0,0 +1,149 @@
+package clienthttp_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTokenServer(t *testing.T, calls *int32, expiresIn int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+
+		assert.True(t, ok)
+		assert.Equal(t, "client-id", id)
+		assert.Equal(t, "client-secret", secret)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "read write", r.PostForm.Get("scope"))
+
+		n := atomic.AddInt32(calls, 1)
+
+		time.Sleep(20 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, n, expiresIn)
+	}))
+}
+
+func TestClientCredentials(t *testing.T) {
+	conf := func(tokenURL string) clienthttp.ClientCredentialsConfig {
+		return clienthttp.ClientCredentialsConfig{
+			TokenURL:     tokenURL + "/oauth/token",
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			Scopes:       []string{"read", "write"},
+		}
+	}
+
+	t.Run("should fetch the token once for concurrent callers [CACHE]", func(t *testing.T) {
+		var calls int32
+
+		ts := newTokenServer(t, &calls, 3600)
+		defer ts.Close()
+
+		source := clienthttp.NewClientCredentials(conf(ts.URL))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				token, err := source.Token(context.TODO())
+
+				assert.NoError(t, err)
+				assert.Equal(t, "token-1", token.AccessToken)
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should refresh the token shortly before expiry [EXPIRY]", func(t *testing.T) {
+		var calls int32
+
+		ts := newTokenServer(t, &calls, 10)
+		defer ts.Close()
+
+		source := clienthttp.NewClientCredentials(conf(ts.URL))
+
+		first, err := source.Token(context.TODO())
+		assert.NoError(t, err)
+
+		second, err := source.Token(context.TODO())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "token-1", first.AccessToken)
+		assert.Equal(t, "token-2", second.AccessToken)
+	})
+
+	t.Run("should return HTTPError when the token endpoint fails [ERROR]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client"}`)
+		}))
+		defer ts.Close()
+
+		_, err := clienthttp.NewClientCredentials(conf(ts.URL)).Token(context.TODO())
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "status Code [ 401 ]")
+	})
+}
+
+func TestClientHttp_WithAuth(t *testing.T) {
+	t.Run("should inject the bearer token and retry once on 401 [REFRESH]", func(t *testing.T) {
+		var tokenCalls, apiCalls int32
+
+		tokenServer := newTokenServer(t, &tokenCalls, 3600)
+		defer tokenServer.Close()
+
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&apiCalls, 1)
+
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, `{"id":1,"name":"diego"}`, string(body))
+
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer api.Close()
+
+		source := clienthttp.NewClientCredentials(clienthttp.ClientCredentialsConfig{
+			TokenURL:     tokenServer.URL + "/oauth/token",
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			Scopes:       []string{"read", "write"},
+		})
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, api.URL, clienthttp.WithAuth(source))
+
+		req := clienthttp.NewRequest(http.MethodPost, "/users").
+			WithJSONBody(userFixture{ID: 1, Name: "diego"}).
+			Build()
+
+		_, statusCode, err := client.Do(context.TODO(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, statusCode)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&apiCalls))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&tokenCalls))
+	})
+}
@@ -0,0 +1,328 @@
+package clienthttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var ErrMaxPagesExceeded = errors.New("paginator [max pages exceeded]")
+
+// PageResponse is what a PageStrategy sees of the page just fetched.
+type PageResponse[R any] struct {
+	Body   R
+	Header http.Header
+	// Items is the number of items extracted from Body.
+	Items int
+	// Number starts at 1.
+	Number int
+}
+
+// PageStrategy shapes the first request and derives each following one from
+// the previous page, returning false once there are no more pages.
+type PageStrategy[R any] interface {
+	First(request HTTPRequestBuilder) HTTPRequestBuilder
+	Next(request HTTPRequestBuilder, page PageResponse[R]) (HTTPRequestBuilder, bool)
+}
+
+// CursorPagination sends the cursor extracted from each page in the param
+// query parameter; an empty cursor ends the iteration.
+func CursorPagination[R any](param string, cursor func(R) string) PageStrategy[R] {
+	return cursorPagination[R]{param: param, cursor: cursor}
+}
+
+type cursorPagination[R any] struct {
+	param  string
+	cursor func(R) string
+}
+
+func (p cursorPagination[R]) First(request HTTPRequestBuilder) HTTPRequestBuilder {
+	return request
+}
+
+func (p cursorPagination[R]) Next(request HTTPRequestBuilder, page PageResponse[R]) (HTTPRequestBuilder, bool) {
+	next := p.cursor(page.Body)
+	if next == "" {
+		return request, false
+	}
+
+	return request.WithQueryParam(p.param, next), true
+}
+
+// OffsetPagination requests limit items per page, advancing offsetParam by
+// the items received; a short page ends the iteration.
+func OffsetPagination[R any](offsetParam, limitParam string, limit int) PageStrategy[R] {
+	return offsetPagination[R]{offsetParam: offsetParam, limitParam: limitParam, limit: limit, countItems: true}
+}
+
+// PageNumberPagination requests size items per page, counting pageParam up
+// from 1; a short page ends the iteration.
+func PageNumberPagination[R any](pageParam, sizeParam string, size int) PageStrategy[R] {
+	return offsetPagination[R]{offsetParam: pageParam, limitParam: sizeParam, limit: size, start: 1}
+}
+
+type offsetPagination[R any] struct {
+	offsetParam string
+	limitParam  string
+	limit       int
+	start       int
+	countItems  bool
+}
+
+func (p offsetPagination[R]) First(request HTTPRequestBuilder) HTTPRequestBuilder {
+	return request.
+		WithQueryParam(p.offsetParam, strconv.Itoa(p.start)).
+		WithQueryParam(p.limitParam, strconv.Itoa(p.limit))
+}
+
+func (p offsetPagination[R]) Next(request HTTPRequestBuilder, page PageResponse[R]) (HTTPRequestBuilder, bool) {
+	if page.Items == 0 || page.Items < p.limit {
+		return request, false
+	}
+
+	offset, err := strconv.Atoi(request.query.Get(p.offsetParam))
+	if err != nil {
+		offset = p.start
+	}
+
+	step := 1
+	if p.countItems {
+		step = page.Items
+	}
+
+	return request.WithQueryParam(p.offsetParam, strconv.Itoa(offset+step)), true
+}
+
+// LinkPagination follows the RFC 5988 Link header with rel="next". The next
+// URL keeps the template method and headers but replaces path and query.
+func LinkPagination[R any]() PageStrategy[R] {
+	return linkPagination[R]{}
+}
+
+type linkPagination[R any] struct{}
+
+func (p linkPagination[R]) First(request HTTPRequestBuilder) HTTPRequestBuilder {
+	return request
+}
+
+func (p linkPagination[R]) Next(request HTTPRequestBuilder, page PageResponse[R]) (HTTPRequestBuilder, bool) {
+	next, ok := nextLink(page.Header)
+	if !ok {
+		return request, false
+	}
+
+	u, err := url.Parse(next)
+	if err != nil {
+		return request.withError(fmt.Errorf("paginator [ invalid next link %q ]: %w", next, err)), true
+	}
+
+	request.url = u.EscapedPath()
+	request.query = u.Query()
+
+	return request, true
+}
+
+func nextLink(header http.Header) (string, bool) {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "rel") {
+					continue
+				}
+
+				for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1], true
+					}
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Paginator fetches pages of R lazily and yields the items of type T each
+// page holds. Pages are decoded like Send does, so non-2xx answers are
+// returned as *HTTPError.
+type Paginator[R, T any] struct {
+	client   HandlerClient
+	request  HTTPRequestBuilder
+	strategy PageStrategy[R]
+	items    func(R) []T
+	maxPages int
+}
+
+func NewPaginator[R, T any](c HandlerClient, request HTTPRequestBuilder, strategy PageStrategy[R], items func(R) []T) *Paginator[R, T] {
+	return &Paginator[R, T]{
+		client:   c,
+		request:  request,
+		strategy: strategy,
+		items:    items,
+	}
+}
+
+// MaxPages fails with ErrMaxPagesExceeded when more than n pages are
+// available, guarding against servers that never stop paginating.
+func (p *Paginator[R, T]) MaxPages(n int) *Paginator[R, T] {
+	p.maxPages = n
+	return p
+}
+
+// ForEachPage calls fn with the items of every page until the last page,
+// fn returns an error or ctx is done.
+func (p *Paginator[R, T]) ForEachPage(ctx context.Context, fn func(items []T, page PageResponse[R]) error) error {
+	request := p.strategy.First(p.request.clone())
+
+	for number := 1; ; number++ {
+		if p.maxPages > 0 && number > p.maxPages {
+			return ErrMaxPagesExceeded
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		items, page, err := p.fetch(ctx, request, number)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(items, page); err != nil {
+			return err
+		}
+
+		next, ok := p.strategy.Next(request.clone(), page)
+		if !ok {
+			return nil
+		}
+
+		request = next
+	}
+}
+
+// ForEach calls fn with every item, in order, across all pages.
+func (p *Paginator[R, T]) ForEach(ctx context.Context, fn func(item T) error) error {
+	return p.ForEachPage(ctx, func(items []T, _ PageResponse[R]) error {
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (p *Paginator[R, T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+
+	err := p.ForEach(ctx, func(item T) error {
+		all = append(all, item)
+		return nil
+	})
+
+	return all, err
+}
+
+// Iterate returns an iterator fetching each page only once its previous
+// items were consumed.
+func (p *Paginator[R, T]) Iterate(ctx context.Context) *PageIterator[R, T] {
+	return &PageIterator[R, T]{
+		ctx:       ctx,
+		paginator: p,
+		request:   p.strategy.First(p.request.clone()),
+		more:      true,
+	}
+}
+
+func (p *Paginator[R, T]) fetch(ctx context.Context, builder HTTPRequestBuilder, number int) ([]T, PageResponse[R], error) {
+	var body R
+
+	req, err := builder.BuildE()
+	if err != nil {
+		return nil, PageResponse[R]{}, err
+	}
+
+	handled, err := p.client.Handle(ctx, req, NewResponseHandler().OnSuccess(&body))
+	if err != nil {
+		return nil, PageResponse[R]{}, err
+	}
+
+	items := p.items(body)
+
+	return items, PageResponse[R]{
+		Body:   body,
+		Header: handled.Header,
+		Items:  len(items),
+		Number: number,
+	}, nil
+}
+
+// PageIterator walks items like bufio.Scanner: call Next until it returns
+// false, then check Err.
+type PageIterator[R, T any] struct {
+	ctx       context.Context
+	paginator *Paginator[R, T]
+	request   HTTPRequestBuilder
+	more      bool
+	pages     int
+
+	items []T
+	item  T
+	err   error
+}
+
+func (it *PageIterator[R, T]) Next() bool {
+	for len(it.items) == 0 {
+		if !it.more || it.err != nil {
+			return false
+		}
+
+		it.err = it.nextPage()
+	}
+
+	it.item, it.items = it.items[0], it.items[1:]
+
+	return true
+}
+
+func (it *PageIterator[R, T]) Item() T {
+	return it.item
+}
+
+func (it *PageIterator[R, T]) Err() error {
+	return it.err
+}
+
+func (it *PageIterator[R, T]) nextPage() error {
+	if max := it.paginator.maxPages; max > 0 && it.pages >= max {
+		return ErrMaxPagesExceeded
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+
+	it.pages++
+
+	items, page, err := it.paginator.fetch(it.ctx, it.request, it.pages)
+	if err != nil {
+		return err
+	}
+
+	it.items = items
+	it.request, it.more = it.paginator.strategy.Next(it.request.clone(), page)
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package clienthttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+type usersPage struct {
+	Users []int  `json:"users"`
+	Next  string `json:"next"`
+}
+
+func usersOf(page usersPage) []int {
+	return page.Users
+}
+
+func TestPaginator(t *testing.T) {
+	t.Run("should follow cursors lazily through the iterator [CURSOR]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			assert.Equal(t, "acme", r.URL.Query().Get("tenant"))
+
+			pages := map[string]usersPage{
+				"":   {Users: []int{1, 2}, Next: "c2"},
+				"c2": {Users: []int{3}, Next: "c3"},
+				"c3": {Users: []int{4}},
+			}
+
+			json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+		request := clienthttp.NewRequest(http.MethodGet, "/users").WithQueryParam("tenant", "acme")
+
+		paginator := clienthttp.NewPaginator(client, request,
+			clienthttp.CursorPagination("cursor", func(page usersPage) string { return page.Next }), usersOf)
+
+		it := paginator.Iterate(context.TODO())
+
+		assert.True(t, it.Next())
+		assert.Equal(t, 1, it.Item())
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		var users []int
+		for it.Next() {
+			users = append(users, it.Item())
+		}
+
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []int{2, 3, 4}, users)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should send the template body with every page [BODY]", func(t *testing.T) {
+		var bodies []string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+
+			if r.URL.Query().Get("cursor") == "" {
+				fmt.Fprint(w, `{"users":[1],"next":"c2"}`)
+				return
+			}
+
+			fmt.Fprint(w, `{"users":[2]}`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+		request := clienthttp.NewRequest(http.MethodPost, "/users/search").WithJSONBody(map[string]string{"status": "active"})
+
+		users, err := clienthttp.NewPaginator(client, request,
+			clienthttp.CursorPagination("cursor", func(page usersPage) string { return page.Next }), usersOf).All(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, users)
+		assert.Equal(t, []string{`{"status":"active"}`, `{"status":"active"}`}, bodies)
+	})
+
+	t.Run("should page by offset until a short page [OFFSET]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			assert.Equal(t, "2", r.URL.Query().Get("limit"))
+
+			var page usersPage
+			for id := offset + 1; id <= offset+2 && id <= 5; id++ {
+				page.Users = append(page.Users, id)
+			}
+
+			json.NewEncoder(w).Encode(page)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		users, err := clienthttp.NewPaginator(client, clienthttp.NewRequest(http.MethodGet, "/users"),
+			clienthttp.OffsetPagination[usersPage]("offset", "limit", 2), usersOf).All(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, users)
+	})
+
+	t.Run("should count page numbers from one [PAGE]", func(t *testing.T) {
+		var requested []string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = append(requested, r.URL.Query().Get("page"))
+
+			if r.URL.Query().Get("page") == "3" {
+				fmt.Fprint(w, `{"users":[]}`)
+				return
+			}
+
+			fmt.Fprint(w, `{"users":[1,2]}`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		users, err := clienthttp.NewPaginator(client, clienthttp.NewRequest(http.MethodGet, "/users"),
+			clienthttp.PageNumberPagination[usersPage]("page", "size", 2), usersOf).All(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Len(t, users, 4)
+		assert.Equal(t, []string{"1", "2", "3"}, requested)
+	})
+
+	t.Run("should follow Link rel=next headers [LINK]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "token", r.Header.Get("Authorization"))
+
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", `<http://`+r.Host+`/v2/users?page=2>; rel="next", <http://`+r.Host+`/v2/users?page=9>; rel="last"`)
+				fmt.Fprint(w, `[1,2]`)
+				return
+			}
+
+			assert.Equal(t, "/v2/users", r.URL.Path)
+			w.Header().Set("Link", `<http://`+r.Host+`/v2/users>; rel="first"`)
+			fmt.Fprint(w, `[3]`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+		request := clienthttp.NewRequest(http.MethodGet, "/users").WithHeader("Authorization", "token")
+
+		var pages []int
+
+		err := clienthttp.NewPaginator(client, request, clienthttp.LinkPagination[[]int](), func(page []int) []int { return page }).
+			ForEachPage(context.TODO(), func(items []int, page clienthttp.PageResponse[[]int]) error {
+				pages = append(pages, page.Number)
+				return nil
+			})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, pages)
+	})
+
+	t.Run("should keep escaped segments of the next link [LINK ESCAPED]", func(t *testing.T) {
+		var paths []string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.EscapedPath()+"?"+r.URL.RawQuery)
+
+			if r.URL.Path == "/files" {
+				w.Header().Set("Link", `<http://`+r.Host+`/files/a%2Fb%3Fc?page=2>; rel="next"`)
+			}
+
+			fmt.Fprint(w, `[1]`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		_, err := clienthttp.NewPaginator(client, clienthttp.NewRequest(http.MethodGet, "/files"),
+			clienthttp.LinkPagination[[]int](), func(page []int) []int { return page }).All(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/files?", "/files/a%2Fb%3Fc?page=2"}, paths)
+	})
+
+	t.Run("should stop at the max pages guard [MAX PAGES]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"users":[1],"next":"again"}`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		var users []int
+
+		err := clienthttp.NewPaginator(client, clienthttp.NewRequest(http.MethodGet, "/users"),
+			clienthttp.CursorPagination("cursor", func(page usersPage) string { return page.Next }), usersOf).
+			MaxPages(3).
+			ForEach(context.TODO(), func(user int) error {
+				users = append(users, user)
+				return nil
+			})
+
+		assert.ErrorIs(t, err, clienthttp.ErrMaxPagesExceeded)
+		assert.Len(t, users, 3)
+	})
+
+	t.Run("should stop when the context is cancelled [CANCEL]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"users":[1],"next":"again"}`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		it := clienthttp.NewPaginator(client, clienthttp.NewRequest(http.MethodGet, "/users"),
+			clienthttp.CursorPagination("cursor", func(page usersPage) string { return page.Next }), usersOf).
+			Iterate(ctx)
+
+		assert.True(t, it.Next())
+		cancel()
+
+		assert.False(t, it.Next())
+		assert.ErrorIs(t, it.Err(), context.Canceled)
+	})
+}
@@ -0,0 +1,146 @@
+package clienthttp
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func encodeQueryStruct(v interface{}) (url.Values, error) {
+	values := url.Values{}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return values, nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("request builder [ query struct %T is not a struct ]", v)
+	}
+
+	if err := encodeStructFields(rv, values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func encodeStructFields(rv reflect.Value, values url.Values) error {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty := parseQueryTag(field)
+		if name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		if field.Anonymous && field.Tag.Get("url") == "" {
+			for fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					break
+				}
+
+				fv = fv.Elem()
+			}
+
+			if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+				if err := encodeStructFields(fv, values); err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		if err := encodeQueryValue(name, fv, values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseQueryTag(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("url")
+	name, opts, _ := strings.Cut(tag, ",")
+
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, opts == "omitempty"
+}
+
+func encodeQueryValue(name string, fv reflect.Value, values url.Values) error {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+
+		fv = fv.Elem()
+	}
+
+	if fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array {
+		for i := 0; i < fv.Len(); i++ {
+			if err := encodeQueryValue(name, fv.Index(i), values); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	value, err := formatQueryValue(fv)
+	if err != nil {
+		return fmt.Errorf("request builder [ query field %s ]: %w", name, err)
+	}
+
+	values.Add(name, value)
+
+	return nil
+}
+
+func formatQueryValue(fv reflect.Value) (string, error) {
+	if fv.Type() == timeType {
+		return fv.Interface().(time.Time).Format(time.RFC3339), nil
+	}
+
+	if stringer, ok := fv.Interface().(fmt.Stringer); ok {
+		return stringer.String(), nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'f', -1, fv.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", fv.Type())
+}
@@ -0,0 +1,263 @@
+package clienthttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/redis"
+)
+
+var ErrRateLimited = errors.New("rate limiter [limit exceeded]")
+
+type RateLimitMode int
+
+const (
+	RateLimitWait RateLimitMode = iota
+	RateLimitFail
+)
+
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+type EndpointRateLimit struct {
+	Pattern string
+	RateLimit
+}
+
+type RateLimitConfig struct {
+	RateLimit
+	Endpoints []EndpointRateLimit
+	Mode      RateLimitMode
+	// Redis shares the buckets between replicas.
+	Redis     redis.Evaler
+	KeyPrefix string
+}
+
+func WithRateLimit(conf RateLimitConfig) Option {
+	return func(c *clientHttp) {
+		c.rateLimit = &conf
+	}
+}
+
+func (l RateLimit) enabled() bool {
+	return l.Requests > 0
+}
+
+func (l RateLimit) rate() float64 {
+	per := l.Per
+	if per <= 0 {
+		per = time.Second
+	}
+
+	return float64(l.Requests) / per.Seconds()
+}
+
+func (l RateLimit) burst() float64 {
+	if l.Burst < 1 {
+		return math.Max(float64(l.Requests), 1)
+	}
+
+	return float64(l.Burst)
+}
+
+type bucketStore interface {
+	take(ctx context.Context, key string, limit RateLimit, maxWait time.Duration) (time.Duration, bool, error)
+}
+
+type rateLimitClient struct {
+	next    ClientAPI
+	conf    RateLimitConfig
+	buckets bucketStore
+	now     func() time.Time
+}
+
+func newRateLimitClient(next ClientAPI, conf RateLimitConfig) *rateLimitClient {
+	if conf.KeyPrefix == "" {
+		conf.KeyPrefix = "ratelimit:"
+	}
+
+	c := &rateLimitClient{
+		next: next,
+		conf: conf,
+		now:  time.Now,
+	}
+
+	if conf.Redis != nil {
+		c.buckets = &redisBuckets{client: conf.Redis}
+	} else {
+		c.buckets = &localBuckets{buckets: make(map[string]*tokenBucket), now: func() time.Time { return c.now() }}
+	}
+
+	return c
+}
+
+func (c *rateLimitClient) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	key := c.conf.KeyPrefix + req.URL.Host
+
+	if c.conf.enabled() {
+		if err := c.acquire(ctx, key, c.conf.RateLimit); err != nil {
+			closeRequestBody(req)
+			return nil, err
+		}
+	}
+
+	for _, endpoint := range c.conf.Endpoints {
+		if matched, _ := path.Match(endpoint.Pattern, req.URL.Path); matched && endpoint.enabled() {
+			if err := c.acquire(ctx, key+endpoint.Pattern, endpoint.RateLimit); err != nil {
+				closeRequestBody(req)
+				return nil, err
+			}
+
+			break
+		}
+	}
+
+	return c.next.Do(req)
+}
+
+func (c *rateLimitClient) acquire(ctx context.Context, key string, limit RateLimit) error {
+	maxWait := c.maxWait(ctx)
+
+	wait, ok, err := c.buckets.take(ctx, key, limit, maxWait)
+	if err != nil {
+		return fmt.Errorf("rate limiter [%s]: %w", key, err)
+	}
+
+	if !ok {
+		return ErrRateLimited
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// maxWait returns how long a request may queue for a token, or a negative
+// duration when it may wait indefinitely.
+func (c *rateLimitClient) maxWait(ctx context.Context) time.Duration {
+	if c.conf.Mode == RateLimitFail {
+		return 0
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return -1
+	}
+
+	return deadline.Sub(c.now())
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type localBuckets struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+func (b *localBuckets) take(_ context.Context, key string, limit RateLimit, maxWait time.Duration) (time.Duration, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	bucket, ok := b.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: limit.burst(), last: now}
+		b.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.last).Seconds()
+	if elapsed > 0 {
+		bucket.tokens = math.Min(limit.burst(), bucket.tokens+elapsed*limit.rate())
+		bucket.last = now
+	}
+
+	var wait time.Duration
+	if bucket.tokens < 1 {
+		wait = time.Duration((1 - bucket.tokens) / limit.rate() * float64(time.Second))
+	}
+
+	if maxWait >= 0 && wait > maxWait {
+		return wait, false, nil
+	}
+
+	bucket.tokens--
+
+	return wait, true, nil
+}
+
+// tokenBucketScript refills and takes from a bucket stored as a hash, using
+// the server clock so every replica shares the same notion of time. It
+// returns {allowed, wait_ms}.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local max_wait = tonumber(ARGV[3])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local wait = 0
+if tokens < 1 then
+  wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+if max_wait >= 0 and wait > max_wait then
+  return {0, wait}
+end
+tokens = tokens - 1
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + wait)
+return {1, wait}
+`
+
+type redisBuckets struct {
+	client redis.Evaler
+}
+
+func (b *redisBuckets) take(ctx context.Context, key string, limit RateLimit, maxWait time.Duration) (time.Duration, bool, error) {
+	maxWaitMillis := int64(-1)
+	if maxWait >= 0 {
+		maxWaitMillis = maxWait.Milliseconds()
+	}
+
+	result, err := b.client.Eval(ctx, tokenBucketScript, []string{key}, limit.rate(), limit.burst(), maxWaitMillis)
+	if err != nil {
+		return 0, false, err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		return 0, false, fmt.Errorf("unexpected script result %v", result)
+	}
+
+	allowed, _ := values[0].(int64)
+	waitMillis, _ := values[1].(int64)
+
+	return time.Duration(waitMillis) * time.Millisecond, allowed == 1, nil
+}
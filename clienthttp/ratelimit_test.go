@@ -0,0 +1,159 @@
+package clienthttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRateLimitServer(t *testing.T) (*httptest.Server, *int32) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, &calls
+}
+
+func TestClientHttp_WithRateLimit(t *testing.T) {
+	t.Run("should fail fast once the domain quota is exhausted [FAIL]", func(t *testing.T) {
+		ts, calls := setupRateLimitServer(t)
+
+		client := NewClientHTTP(http.DefaultClient, ts.URL, WithRateLimit(RateLimitConfig{
+			RateLimit: RateLimit{Requests: 2, Per: time.Minute},
+			Mode:      RateLimitFail,
+		}))
+
+		for i := 0; i < 2; i++ {
+			_, _, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+			assert.NoError(t, err)
+		}
+
+		_, _, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	})
+
+	t.Run("should wait for a token when there is time left [WAIT]", func(t *testing.T) {
+		ts, calls := setupRateLimitServer(t)
+
+		client := NewClientHTTP(http.DefaultClient, ts.URL, WithRateLimit(RateLimitConfig{
+			RateLimit: RateLimit{Requests: 20, Per: time.Second, Burst: 1},
+		}))
+
+		start := time.Now()
+
+		for i := 0; i < 3; i++ {
+			_, _, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+			assert.NoError(t, err)
+		}
+
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+		assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+	})
+
+	t.Run("should not wait past the context deadline [DEADLINE]", func(t *testing.T) {
+		ts, calls := setupRateLimitServer(t)
+
+		client := NewClientHTTP(http.DefaultClient, ts.URL, WithRateLimit(RateLimitConfig{
+			RateLimit: RateLimit{Requests: 1, Per: time.Minute},
+		}))
+
+		_, _, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		start := time.Now()
+
+		_, _, err = client.Do(ctx, NewRequest(http.MethodGet, "/users").Build())
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	})
+
+	t.Run("should limit matching endpoints independently [ENDPOINT]", func(t *testing.T) {
+		ts, calls := setupRateLimitServer(t)
+
+		client := NewClientHTTP(http.DefaultClient, ts.URL, WithRateLimit(RateLimitConfig{
+			Endpoints: []EndpointRateLimit{{Pattern: "/reports/*", RateLimit: RateLimit{Requests: 1, Per: time.Minute}}},
+			Mode:      RateLimitFail,
+		}))
+
+		_, _, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/reports/1").Build())
+		assert.NoError(t, err)
+
+		_, _, err = client.Do(context.TODO(), NewRequest(http.MethodGet, "/reports/2").Build())
+		assert.ErrorIs(t, err, ErrRateLimited)
+
+		_, _, err = client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+		assert.NoError(t, err)
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	})
+
+	t.Run("should not retry requests rejected by the limiter [RETRY]", func(t *testing.T) {
+		ts, calls := setupRateLimitServer(t)
+
+		client := NewClientHTTP(http.DefaultClient, ts.URL,
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+			WithRateLimit(RateLimitConfig{RateLimit: RateLimit{Requests: 1, Per: time.Minute}, Mode: RateLimitFail}),
+		)
+
+		_, _, _ = client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+		_, _, err := client.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	})
+
+	t.Run("should share the quota between replicas through redis [DISTRIBUTED]", func(t *testing.T) {
+		ts, calls := setupRateLimitServer(t)
+		store := newFakeRedis()
+
+		conf := RateLimitConfig{RateLimit: RateLimit{Requests: 2, Per: time.Minute}, Mode: RateLimitFail, Redis: store}
+		replicaA := NewClientHTTP(http.DefaultClient, ts.URL, WithRateLimit(conf))
+		replicaB := NewClientHTTP(http.DefaultClient, ts.URL, WithRateLimit(conf))
+
+		_, _, err := replicaA.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+		assert.NoError(t, err)
+
+		_, _, err = replicaB.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+		assert.NoError(t, err)
+
+		_, _, err = replicaA.Do(context.TODO(), NewRequest(http.MethodGet, "/users").Build())
+		assert.ErrorIs(t, err, ErrRateLimited)
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	})
+}
+
+func TestLocalBuckets(t *testing.T) {
+	t.Run("should refill tokens at the configured rate", func(t *testing.T) {
+		clock := &fakeClock{now: time.Unix(1700000000, 0)}
+		buckets := &localBuckets{buckets: make(map[string]*tokenBucket), now: clock.Now}
+		limit := RateLimit{Requests: 10, Per: time.Second, Burst: 1}
+
+		_, ok, _ := buckets.take(context.TODO(), "key", limit, 0)
+		assert.True(t, ok)
+
+		wait, ok, _ := buckets.take(context.TODO(), "key", limit, 0)
+		assert.False(t, ok)
+		assert.Equal(t, 100*time.Millisecond, wait)
+
+		clock.Advance(100 * time.Millisecond)
+
+		_, ok, _ = buckets.take(context.TODO(), "key", limit, 0)
+		assert.True(t, ok)
+	})
+}
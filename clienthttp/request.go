@@ -2,55 +2,320 @@ package clienthttp
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
 )
 
+var pathParamPattern = regexp.MustCompile(`\{[^{}/]+\}`)
+
 type HTTPRequestBuilder struct {
-	method  string
-	url     string
-	headers map[string]string
-	query   map[string]string
-	body    io.Reader
+	method     string
+	url        string
+	headers    http.Header
+	query      url.Values
+	pathParams map[string]string
+	cookies    []*http.Cookie
+	body       []byte
+	bodyKind   string
+	multipart  []multipartEntry
+	errs       []error
 }
 
 func NewRequest(method, url string) HTTPRequestBuilder {
 	return HTTPRequestBuilder{
-		method:  method,
-		url:     url,
-		headers: make(map[string]string),
-		query:   make(map[string]string),
+		method:     method,
+		url:        url,
+		headers:    make(http.Header),
+		query:      make(map[string][]string),
+		pathParams: make(map[string]string),
 	}
 }
 
 func (h HTTPRequestBuilder) WithHeader(key, value string) HTTPRequestBuilder {
-	h.headers[key] = value
+	if err := validateHeader(key, value); err != nil {
+		return h.withError(err)
+	}
+
+	h.headers.Set(key, value)
+	return h
+}
+
+func (h HTTPRequestBuilder) AddHeader(key, value string) HTTPRequestBuilder {
+	if err := validateHeader(key, value); err != nil {
+		return h.withError(err)
+	}
+
+	h.headers.Add(key, value)
 	return h
 }
 
 func (h HTTPRequestBuilder) WithQueryParam(key, value string) HTTPRequestBuilder {
-	h.query[key] = value
+	h.query.Set(key, value)
+	return h
+}
+
+func (h HTTPRequestBuilder) AddQueryParam(key string, values ...string) HTTPRequestBuilder {
+	for _, value := range values {
+		h.query.Add(key, value)
+	}
+
+	return h
+}
+
+func (h HTTPRequestBuilder) WithQueryStruct(v interface{}) HTTPRequestBuilder {
+	values, err := encodeQueryStruct(v)
+	if err != nil {
+		return h.withError(err)
+	}
+
+	for key, value := range values {
+		h.query[key] = value
+	}
+
+	return h
+}
+
+func (h HTTPRequestBuilder) WithPathParam(key, value string) HTTPRequestBuilder {
+	h.pathParams[key] = value
+	return h
+}
+
+func (h HTTPRequestBuilder) WithCookie(cookie *http.Cookie) HTTPRequestBuilder {
+	if err := cookie.Valid(); err != nil {
+		return h.withError(fmt.Errorf("request builder [ invalid cookie ]: %w", err))
+	}
+
+	h.cookies = append(h.cookies[:len(h.cookies):len(h.cookies)], cookie)
 	return h
 }
 
 func (h HTTPRequestBuilder) WithBodyBytes(body []byte) HTTPRequestBuilder {
-	h.body = bytes.NewBuffer(body)
+	return h.withBody("bytes", body)
+}
+
+func (h HTTPRequestBuilder) WithBody(codec Codec, v interface{}) HTTPRequestBuilder {
+	body, err := codec.Marshal(v)
+	if err != nil {
+		return h.withError(fmt.Errorf("request builder [ %s ] body: %w", codec.ContentType(), err))
+	}
+
+	h = h.withBody(codec.ContentType(), body)
+	h.headers.Set("Content-Type", codec.ContentType())
+
 	return h
 }
 
-func (h HTTPRequestBuilder) Build() *http.Request {
-	req, _ := http.NewRequest(h.method, h.url, h.body)
+func (h HTTPRequestBuilder) WithJSONBody(v interface{}) HTTPRequestBuilder {
+	return h.WithBody(JSONCodec, v)
+}
+
+func (h HTTPRequestBuilder) WithXMLBody(v interface{}) HTTPRequestBuilder {
+	return h.WithBody(XMLCodec, v)
+}
+
+func (h HTTPRequestBuilder) WithFormBody(values url.Values) HTTPRequestBuilder {
+	return h.WithBody(FormCodec, values)
+}
+
+func (h HTTPRequestBuilder) Validate() error {
+	errs := append([]error(nil), h.errs...)
+
+	if !validToken(h.method) {
+		errs = append(errs, fmt.Errorf("request builder [ invalid method %q ]", h.method))
+	}
+
+	rawURL, err := h.renderURL()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if _, err = url.Parse(rawURL); err != nil {
+		errs = append(errs, fmt.Errorf("request builder [ invalid url ]: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h HTTPRequestBuilder) BuildE() (*http.Request, error) {
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+
+	rawURL, _ := h.renderURL()
+
+	// Each request reads its own copy of the body, so a builder can be built
+	// again, as the paginator does for every page.
+	var body io.Reader
+	if h.body != nil {
+		body = bytes.NewReader(h.body)
+	}
+
+	contentType := ""
+	if len(h.multipart) > 0 {
+		body, contentType = h.multipartBody()
+	}
+
+	req, err := http.NewRequest(h.method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range h.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
 
-	for key, value := range h.headers {
-		req.Header.Set(key, value)
+	for _, cookie := range h.cookies {
+		req.AddCookie(cookie)
 	}
 
 	q := req.URL.Query()
-	for k, v := range h.query {
-		q.Add(k, v)
+	for k, values := range h.query {
+		for _, v := range values {
+			q.Add(k, v)
+		}
 	}
 
 	req.URL.RawQuery = q.Encode()
 
-	return req
+	return req, nil
+}
+
+// Build suits call sites passing the request straight to ClientHTTP. When the
+// builder is invalid it returns a request that fails with the BuildE error
+// once sent, instead of nil; prefer BuildE to handle it upfront.
+func (h HTTPRequestBuilder) Build() *http.Request {
+	req, err := h.BuildE()
+	if err == nil {
+		return req
+	}
+
+	failed := &http.Request{
+		Method:     h.method,
+		URL:        &url.URL{Path: h.url},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     h.headers.Clone(),
+		Body:       failedBody{err: err},
+	}
+
+	return failed.WithContext(context.WithValue(context.Background(), buildErrorKey{}, err))
+}
+
+type buildErrorKey struct{}
+
+func buildError(req *http.Request) error {
+	err, _ := req.Context().Value(buildErrorKey{}).(error)
+	return err
+}
+
+// failedBody also fails requests from Build sent by other means than
+// ClientHTTP, such as a plain http.Client.
+type failedBody struct {
+	err error
+}
+
+func (b failedBody) Read([]byte) (int, error) {
+	return 0, b.err
+}
+
+func (b failedBody) Close() error {
+	return nil
+}
+
+func (h HTTPRequestBuilder) renderURL() (string, error) {
+	var missing []string
+
+	rendered := pathParamPattern.ReplaceAllStringFunc(h.url, func(param string) string {
+		value, ok := h.pathParams[param[1:len(param)-1]]
+		if !ok {
+			missing = append(missing, param)
+			return param
+		}
+
+		return url.PathEscape(value)
+	})
+
+	if len(missing) > 0 {
+		return rendered, fmt.Errorf("request builder [ missing path params %s ]", strings.Join(missing, ", "))
+	}
+
+	return rendered, nil
+}
+
+func (h HTTPRequestBuilder) withBody(kind string, body []byte) HTTPRequestBuilder {
+	if h.bodyKind != "" {
+		return h.withError(fmt.Errorf("request builder [ conflicting body options %s and %s ]", h.bodyKind, kind))
+	}
+
+	h.body = body
+	h.bodyKind = kind
+
+	return h
+}
+
+// clone copies the maps that With* methods mutate in place, so requests
+// derived from a template do not leak parameters back into it.
+func (h HTTPRequestBuilder) clone() HTTPRequestBuilder {
+	h.headers = h.headers.Clone()
+	h.query = url.Values(http.Header(h.query).Clone())
+
+	pathParams := make(map[string]string, len(h.pathParams))
+	for key, value := range h.pathParams {
+		pathParams[key] = value
+	}
+
+	h.pathParams = pathParams
+
+	return h
+}
+
+func (h HTTPRequestBuilder) withError(err error) HTTPRequestBuilder {
+	h.errs = append(h.errs[:len(h.errs):len(h.errs)], err)
+	return h
+}
+
+func validateHeader(key, value string) error {
+	if !validToken(key) {
+		return fmt.Errorf("request builder [ invalid header name %q ]", key)
+	}
+
+	if strings.ContainsAny(value, "\r\n\x00") {
+		return fmt.Errorf("request builder [ invalid header value for %q ]", key)
+	}
+
+	return nil
+}
+
+func validToken(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, r := range value {
+		if r > 127 || !isTokenChar(byte(r)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isTokenChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+
+	return strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
 }
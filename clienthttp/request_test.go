@@ -1,11 +1,14 @@
 package clienthttp_test
 
 import (
+	"context"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
-	"synergetic-craft/clienthttp"
+	"github.com/dot-backend/synergetic-craft/clienthttp"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -28,3 +31,163 @@ func TestHTTPRequestBuilder(t *testing.T) {
 		assert.Equal(t, "Test unit", string(body))
 	})
 }
+
+func TestHTTPRequestBuilder_BuildE(t *testing.T) {
+	t.Run("should return the request when the builder is valid", func(t *testing.T) {
+		request, err := clienthttp.NewRequest(http.MethodPost, "/test/test-1").
+			WithHeader("Test-one", "Test 1").
+			WithJSONBody(map[string]string{"name": "test"}).
+			BuildE()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "POST", request.Method)
+	})
+
+	t.Run("should return error when method is invalid", func(t *testing.T) {
+		request, err := clienthttp.NewRequest("GE T", "/test/test-1").BuildE()
+
+		assert.Nil(t, request)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid method "GE T"`)
+	})
+
+	t.Run("should return error when url is malformed", func(t *testing.T) {
+		request, err := clienthttp.NewRequest(http.MethodGet, "http://[::1:80/test").BuildE()
+
+		assert.Nil(t, request)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid url")
+	})
+
+	t.Run("should return error when body options conflict", func(t *testing.T) {
+		err := clienthttp.NewRequest(http.MethodPost, "/test/test-1").
+			WithBodyBytes([]byte(`raw`)).
+			WithJSONBody(map[string]string{"name": "test"}).
+			Validate()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "conflicting body options bytes and application/json")
+	})
+
+	t.Run("should collect every header injection error", func(t *testing.T) {
+		err := clienthttp.NewRequest(http.MethodGet, "/test/test-1").
+			WithHeader("X-Test", "value\r\nX-Injected: true").
+			WithHeader("Bad Header", "value").
+			Validate()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid header value for "X-Test"`)
+		assert.Contains(t, err.Error(), `invalid header name "Bad Header"`)
+	})
+
+	t.Run("should fail on send when Build is used with an invalid builder", func(t *testing.T) {
+		req := clienthttp.NewRequest(http.MethodGet, "/test/{id}").WithHeader("X-Trace", "a\r\nInjected: true").Build()
+
+		_, _, err := clienthttp.NewClientHTTP(http.DefaultClient, "http://localhost").Do(context.TODO(), req)
+
+		assert.ErrorContains(t, err, "missing path params {id}")
+		assert.ErrorContains(t, err, "X-Trace")
+	})
+}
+
+func TestHTTPRequestBuilder_MultiValues(t *testing.T) {
+	t.Run("should send repeated query params and headers", func(t *testing.T) {
+		request := clienthttp.NewRequest(http.MethodGet, "/test/test-1?fixed=1").
+			AddQueryParam("id", "1", "2").
+			AddQueryParam("id", "3").
+			AddHeader("X-Tag", "a").
+			AddHeader("X-Tag", "b").
+			Build()
+
+		assert.Equal(t, "fixed=1&id=1&id=2&id=3", request.URL.RawQuery)
+		assert.Equal(t, []string{"a", "b"}, request.Header.Values("X-Tag"))
+	})
+
+	t.Run("should render escaped path params", func(t *testing.T) {
+		request := clienthttp.NewRequest(http.MethodGet, "/users/{id}/files/{name}").
+			WithPathParam("id", "42").
+			WithPathParam("name", "report 2024/01?.pdf").
+			Build()
+
+		assert.Equal(t, "/users/42/files/report%202024%2F01%3F.pdf", request.URL.EscapedPath())
+	})
+
+	t.Run("should send escaped path params without decoding them", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/users/a%2Fb%3Fc", r.URL.EscapedPath())
+			assert.Equal(t, "/users/a/b?c", r.URL.Path)
+			assert.Equal(t, "x=1", r.URL.RawQuery)
+		}))
+		defer ts.Close()
+
+		request := clienthttp.NewRequest(http.MethodGet, "/users/{name}").
+			WithPathParam("name", "a/b?c").
+			WithQueryParam("x", "1").
+			Build()
+
+		_, statusCode, err := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).Do(context.TODO(), request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+	})
+
+	t.Run("should return error when a path param is missing", func(t *testing.T) {
+		_, err := clienthttp.NewRequest(http.MethodGet, "/users/{id}/files/{name}").
+			WithPathParam("id", "42").
+			BuildE()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "missing path params {name}")
+	})
+
+	t.Run("should add cookies", func(t *testing.T) {
+		request := clienthttp.NewRequest(http.MethodGet, "/test/test-1").
+			WithCookie(&http.Cookie{Name: "session", Value: "abc"}).
+			WithCookie(&http.Cookie{Name: "lang", Value: "es"}).
+			Build()
+
+		assert.Equal(t, "session=abc; lang=es", request.Header.Get("Cookie"))
+	})
+
+	t.Run("should return error when cookie is invalid", func(t *testing.T) {
+		_, err := clienthttp.NewRequest(http.MethodGet, "/test/test-1").
+			WithCookie(&http.Cookie{Name: "bad name", Value: "abc"}).
+			BuildE()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid cookie")
+	})
+
+	t.Run("should encode query structs using url tags", func(t *testing.T) {
+		type Page struct {
+			Limit  int `url:"limit"`
+			Offset int `url:"offset,omitempty"`
+		}
+
+		limit := 3.5
+
+		filter := struct {
+			Page
+			IDs     []int     `url:"id"`
+			Status  string    `url:"status,omitempty"`
+			Active  *bool     `url:"active"`
+			Ratio   *float64  `url:"ratio"`
+			Since   time.Time `url:"since"`
+			Name    string
+			Ignored string `url:"-"`
+		}{
+			Page:    Page{Limit: 10},
+			IDs:     []int{1, 2},
+			Ratio:   &limit,
+			Since:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Name:    "diego oviedo",
+			Ignored: "ignored",
+		}
+
+		request := clienthttp.NewRequest(http.MethodGet, "/users").
+			WithQueryStruct(filter).
+			Build()
+
+		assert.Equal(t, "Name=diego+oviedo&id=1&id=2&limit=10&ratio=3.5&since=2024-01-02T03%3A04%3A05Z", request.URL.RawQuery)
+	})
+}
@@ -0,0 +1,92 @@
+package clienthttp
+
+import "net/http"
+
+type ResponseHandler struct {
+	routes []responseRoute
+}
+
+type responseRoute struct {
+	from int
+	to   int
+	out  interface{}
+}
+
+type HandledResponse struct {
+	StatusCode int
+	Header     http.Header
+	Target     interface{}
+}
+
+func NewResponseHandler() ResponseHandler {
+	return ResponseHandler{}
+}
+
+func (h ResponseHandler) On(statusCode int, out interface{}) ResponseHandler {
+	return h.OnRange(statusCode, statusCode, out)
+}
+
+func (h ResponseHandler) OnRange(from, to int, out interface{}) ResponseHandler {
+	h.routes = append(h.routes[:len(h.routes):len(h.routes)], responseRoute{
+		from: from,
+		to:   to,
+		out:  out,
+	})
+
+	return h
+}
+
+func (h ResponseHandler) OnSuccess(out interface{}) ResponseHandler {
+	return h.OnRange(200, 299, out)
+}
+
+func (h ResponseHandler) OnClientError(out interface{}) ResponseHandler {
+	return h.OnRange(400, 499, out)
+}
+
+func (h ResponseHandler) OnServerError(out interface{}) ResponseHandler {
+	return h.OnRange(500, 599, out)
+}
+
+func (h ResponseHandler) match(statusCode int) (responseRoute, bool) {
+	for _, r := range h.routes {
+		if r.from == r.to && r.from == statusCode {
+			return r, true
+		}
+	}
+
+	for _, r := range h.routes {
+		if r.from != r.to && statusCode >= r.from && statusCode <= r.to {
+			return r, true
+		}
+	}
+
+	return responseRoute{}, false
+}
+
+func (h ResponseHandler) handle(resp *response) (*HandledResponse, error) {
+	route, ok := h.match(resp.statusCode)
+	if !ok {
+		return nil, newHTTPError(resp)
+	}
+
+	handled := &HandledResponse{
+		StatusCode: resp.statusCode,
+		Header:     resp.header,
+		Target:     route.out,
+	}
+
+	if route.out == nil || len(resp.body) == 0 {
+		return handled, nil
+	}
+
+	if err := decodeBody(resp.header.Get("Content-Type"), resp.body, route.out); err != nil {
+		return handled, err
+	}
+
+	return handled, nil
+}
+
+func (r *HandledResponse) Matched(out interface{}) bool {
+	return r != nil && r.Target == out
+}
@@ -0,0 +1,113 @@
+package clienthttp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+type userFixture struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type problemFixture struct {
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+}
+
+func TestClientHttp_Handle(t *testing.T) {
+	newServer := func(statusCode int, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+
+			fmt.Fprint(w, body)
+		}))
+	}
+
+	t.Run("should decode success range into the success target [CREATED]", func(t *testing.T) {
+		ts := newServer(http.StatusCreated, `{"id":1,"name":"diego"}`)
+		defer ts.Close()
+
+		var (
+			user    userFixture
+			problem problemFixture
+		)
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		result, err := client.Handle(context.TODO(), clienthttp.NewRequest(http.MethodPost, "/unit-test/users").Build(),
+			clienthttp.NewResponseHandler().
+				OnSuccess(&user).
+				OnClientError(&problem))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, result.StatusCode)
+		assert.True(t, result.Matched(&user))
+		assert.Equal(t, userFixture{ID: 1, Name: "diego"}, user)
+	})
+
+	t.Run("should decode client errors into the problem target [CONFLICT]", func(t *testing.T) {
+		ts := newServer(http.StatusConflict, `{"title":"duplicated user","status":409}`)
+		defer ts.Close()
+
+		var (
+			user    userFixture
+			problem problemFixture
+		)
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		result, err := client.Handle(context.TODO(), clienthttp.NewRequest(http.MethodPost, "/unit-test/users").Build(),
+			clienthttp.NewResponseHandler().
+				On(http.StatusOK, &user).
+				On(http.StatusCreated, &user).
+				OnClientError(&problem))
+
+		assert.NoError(t, err)
+		assert.True(t, result.Matched(&problem))
+		assert.Equal(t, problemFixture{Title: "duplicated user", Status: 409}, problem)
+	})
+
+	t.Run("should prefer exact status codes over ranges [NOT FOUND]", func(t *testing.T) {
+		ts := newServer(http.StatusNotFound, ``)
+		defer ts.Close()
+
+		var problem problemFixture
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		result, err := client.Handle(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test/users/1").Build(),
+			clienthttp.NewResponseHandler().
+				OnClientError(&problem).
+				On(http.StatusNotFound, nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, result.StatusCode)
+		assert.False(t, result.Matched(&problem))
+	})
+
+	t.Run("should return HTTPError when no handler matches [UNHANDLED]", func(t *testing.T) {
+		ts := newServer(http.StatusInternalServerError, `boom`)
+		defer ts.Close()
+
+		var user userFixture
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.HandlerClient)
+
+		result, err := client.Handle(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/unit-test/users/1").Build(),
+			clienthttp.NewResponseHandler().OnSuccess(&user))
+
+		var httpErr *clienthttp.HTTPError
+
+		assert.Nil(t, result)
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusInternalServerError, httpErr.StatusCode)
+	})
+}
@@ -0,0 +1,244 @@
+package clienthttp
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const drainBodyLimit = 4096
+
+type RetryPolicy struct {
+	MaxAttempts     int
+	InitialBackoff  time.Duration
+	MaxBackoff      time.Duration
+	MaxRetryAfter   time.Duration
+	Multiplier      float64
+	Jitter          float64
+	RetryableStatus []int
+	ShouldRetry     func(req *http.Request, resp *http.Response, err error) bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     3,
+		InitialBackoff:  100 * time.Millisecond,
+		MaxBackoff:      2 * time.Second,
+		MaxRetryAfter:   10 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+		RetryableStatus: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	}
+}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *clientHttp) {
+		c.retry = &policy
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	def := DefaultRetryPolicy()
+
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = def.InitialBackoff
+	}
+
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = def.MaxBackoff
+	}
+
+	if p.MaxRetryAfter <= 0 {
+		p.MaxRetryAfter = def.MaxRetryAfter
+	}
+
+	if p.Multiplier < 1 {
+		p.Multiplier = def.Multiplier
+	}
+
+	if p.Jitter < 0 || p.Jitter > 1 {
+		p.Jitter = def.Jitter
+	}
+
+	if p.RetryableStatus == nil {
+		p.RetryableStatus = def.RetryableStatus
+	}
+
+	if p.ShouldRetry == nil {
+		p.ShouldRetry = p.shouldRetry
+	}
+
+	return p
+}
+
+func (p RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if !isIdempotent(req) && !hasIdempotencyKey(req) {
+		return false
+	}
+
+	if err != nil {
+		return req.Context().Err() == nil && !errors.Is(err, ErrCircuitOpen) && !errors.Is(err, ErrRateLimited)
+	}
+
+	for _, status := range p.RetryableStatus {
+		if resp.StatusCode == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt))
+	if backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	backoff -= backoff * p.Jitter * rand.Float64()
+
+	return time.Duration(backoff)
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func isReplayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	wait := time.Until(date)
+	if wait < 0 {
+		wait = 0
+	}
+
+	return wait, true
+}
+
+type retryClient struct {
+	next   ClientAPI
+	policy RetryPolicy
+}
+
+func newRetryClient(next ClientAPI, policy RetryPolicy) ClientAPI {
+	return &retryClient{
+		next:   next,
+		policy: policy.withDefaults(),
+	}
+}
+
+func (r *retryClient) Do(req *http.Request) (*http.Response, error) {
+	if !isReplayable(req) {
+		return r.next.Do(req)
+	}
+
+	ctx := req.Context()
+	attemptReq := req
+
+	for attempt := 1; ; attempt++ {
+		resp, err := r.next.Do(attemptReq)
+
+		if attempt >= r.policy.MaxAttempts || !r.policy.ShouldRetry(attemptReq, resp, err) {
+			return resp, err
+		}
+
+		wait := r.policy.backoff(attempt - 1)
+		if after, ok := retryAfter(resp); ok {
+			if after > r.policy.MaxRetryAfter {
+				return resp, err
+			}
+			wait = after
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, err
+		}
+
+		next, errReplay := replayRequest(req)
+		if errReplay != nil {
+			return resp, err
+		}
+
+		discardResponse(resp)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		attemptReq = next
+	}
+}
+
+func replayRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.GetBody == nil {
+		return next, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	next.Body = body
+
+	return next, nil
+}
+
+// closeRequestBody releases the body of a request a layer gives up on before
+// handing it to the transport, which would otherwise close it.
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
+
+func discardResponse(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, drainBodyLimit))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,167 @@
+package clienthttp_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientHttp_Retry(t *testing.T) {
+	policy := clienthttp.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+
+	t.Run("should retry retryable status and replay the body [SUCCESS]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "body byte", string(body))
+
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			fmt.Fprintf(w, "ok")
+		}))
+		defer ts.Close()
+
+		req := clienthttp.NewRequest(http.MethodPut, "/unit-test/retry").
+			WithBodyBytes([]byte("body byte")).
+			Build()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithRetryPolicy(policy))
+
+		resp, statusCode, err := client.Do(context.TODO(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, "ok", string(resp))
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should return last response when attempts are exhausted [EXHAUSTED]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer ts.Close()
+
+		req := clienthttp.NewRequest(http.MethodGet, "/unit-test/retry").Build()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithRetryPolicy(policy))
+
+		_, statusCode, err := client.Do(context.TODO(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, statusCode)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should not retry non idempotent methods [POST]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		req := clienthttp.NewRequest(http.MethodPost, "/unit-test/retry").
+			WithBodyBytes([]byte("body byte")).
+			Build()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithRetryPolicy(policy))
+
+		_, statusCode, err := client.Do(context.TODO(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should honor Retry-After header [RETRY AFTER]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			fmt.Fprintf(w, "ok")
+		}))
+		defer ts.Close()
+
+		req := clienthttp.NewRequest(http.MethodGet, "/unit-test/retry").Build()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithRetryPolicy(policy))
+
+		start := time.Now()
+		_, statusCode, err := client.Do(context.TODO(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+
+	t.Run("should return the response when Retry-After exceeds MaxRetryAfter [MAX RETRY AFTER]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		req := clienthttp.NewRequest(http.MethodGet, "/unit-test/retry").Build()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithRetryPolicy(policy))
+
+		start := time.Now()
+		_, statusCode, err := client.Do(context.TODO(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("should stop retrying when Retry-After exceeds the deadline [TIMEOUT]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("Retry-After", "10")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		req := clienthttp.NewRequest(http.MethodGet, "/unit-test/retry").Build()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithRetryPolicy(policy))
+
+		var out struct{}
+
+		err := client.DoWithTimeout(context.TODO(), req, 500, http.StatusOK, &out)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "status Code [ 503 ]")
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
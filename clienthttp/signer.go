@@ -0,0 +1,405 @@
+package clienthttp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+type SignerFunc func(req *http.Request, body []byte) error
+
+func (f SignerFunc) Sign(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+func WithSigner(signer Signer) Option {
+	return func(c *clientHttp) {
+		c.signer = signer
+	}
+}
+
+func signMiddleware(signer Signer) Middleware {
+	return func(next ClientAPI) ClientAPI {
+		return ClientAPIFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := readReplayableBody(req)
+			if err != nil {
+				return nil, err
+			}
+
+			signed := req.Clone(req.Context())
+			if body != nil {
+				signed.Body = io.NopCloser(bytes.NewReader(body))
+				signed.GetBody = func() (io.ReadCloser, error) {
+					return io.NopCloser(bytes.NewReader(body)), nil
+				}
+				signed.ContentLength = int64(len(body))
+			}
+
+			if err = signer.Sign(signed, body); err != nil {
+				return nil, err
+			}
+
+			return next.Do(signed)
+		})
+	}
+}
+
+func readReplayableBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody == nil {
+		defer req.Body.Close()
+		return io.ReadAll(req.Body)
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
+type HMACSigner struct {
+	Key             []byte
+	KeyID           string
+	SignatureHeader string
+	TimestampHeader string
+	KeyIDHeader     string
+	Now             func() time.Time
+}
+
+func (s *HMACSigner) Sign(req *http.Request, body []byte) error {
+	if len(s.Key) == 0 {
+		return errors.New("hmac signer [key is empty]")
+	}
+
+	timestamp := strconv.FormatInt(now(s.Now).Unix(), 10)
+
+	mac := hmac.New(sha256.New, s.Key)
+	mac.Write([]byte(req.Method + "\n" + req.URL.RequestURI() + "\n" + timestamp + "\n"))
+	mac.Write(body)
+
+	req.Header.Set(headerOrDefault(s.TimestampHeader, "X-Timestamp"), timestamp)
+	req.Header.Set(headerOrDefault(s.SignatureHeader, "X-Signature"), hex.EncodeToString(mac.Sum(nil)))
+
+	if s.KeyID != "" {
+		req.Header.Set(headerOrDefault(s.KeyIDHeader, "X-Key-Id"), s.KeyID)
+	}
+
+	return nil
+}
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+)
+
+type SigV4Signer struct {
+	AccessKey       string
+	SecretKey       string
+	SessionToken    string
+	Region          string
+	Service         string
+	SignPayloadHash bool
+	Now             func() time.Time
+}
+
+func (s *SigV4Signer) Sign(req *http.Request, body []byte) error {
+	if s.AccessKey == "" || s.SecretKey == "" {
+		return errors.New("sigv4 signer [credentials are empty]")
+	}
+
+	amzDate := now(s.Now).UTC().Format(sigV4TimeFormat)
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	if s.SignPayloadHash {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signedHeaders, canonicalHeaders := canonicalSigV4Headers(req)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalSigV4Path(req.URL),
+		canonicalSigV4Query(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.Region, s.Service, "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.AccessKey, scope, signedHeaders, signature))
+
+	return nil
+}
+
+func canonicalSigV4Path(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+
+	return path
+}
+
+func canonicalSigV4Query(u *url.URL) string {
+	query := u.Query()
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(query))
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+
+		for _, value := range values {
+			pairs = append(pairs, sigV4Escape(key)+"="+sigV4Escape(value))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func sigV4Escape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func canonicalSigV4Headers(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{"host": host}
+
+	for key, values := range req.Header {
+		name := strings.ToLower(key)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			trimmed := make([]string, len(values))
+			for i, value := range values {
+				trimmed[i] = strings.Join(strings.Fields(value), " ")
+			}
+
+			headers[name] = strings.Join(trimmed, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	return strings.Join(names, ";"), canonical.String()
+}
+
+type JWTSigner struct {
+	Algorithm string
+	Key       interface{}
+	KeyID     string
+	Issuer    string
+	Subject   string
+	Audience  string
+	TTL       time.Duration
+	Claims    map[string]interface{}
+	Header    string
+	Now       func() time.Time
+}
+
+func (s *JWTSigner) Sign(req *http.Request, body []byte) error {
+	token, err := s.Token()
+	if err != nil {
+		return err
+	}
+
+	header := headerOrDefault(s.Header, "Authorization")
+	if header == "Authorization" {
+		token = "Bearer " + token
+	}
+
+	req.Header.Set(header, token)
+
+	return nil
+}
+
+func (s *JWTSigner) Token() (string, error) {
+	algorithm := s.Algorithm
+	if algorithm == "" {
+		algorithm = "HS256"
+	}
+
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	issuedAt := now(s.Now)
+
+	jti := make([]byte, 16)
+	_, _ = rand.Read(jti)
+
+	claims := make(map[string]interface{}, len(s.Claims)+6)
+	for key, value := range s.Claims {
+		claims[key] = value
+	}
+
+	claims["iat"] = issuedAt.Unix()
+	claims["exp"] = issuedAt.Add(ttl).Unix()
+	claims["jti"] = hex.EncodeToString(jti)
+
+	if s.Issuer != "" {
+		claims["iss"] = s.Issuer
+	}
+
+	if s.Subject != "" {
+		claims["sub"] = s.Subject
+	}
+
+	if s.Audience != "" {
+		claims["aud"] = s.Audience
+	}
+
+	header := map[string]string{"alg": algorithm, "typ": "JWT"}
+	if s.KeyID != "" {
+		header["kid"] = s.KeyID
+	}
+
+	encodedHeader, err := jwtSegment(header)
+	if err != nil {
+		return "", err
+	}
+
+	encodedClaims, err := jwtSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodedHeader + "." + encodedClaims
+
+	signature, err := s.signature(algorithm, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (s *JWTSigner) signature(algorithm string, input []byte) ([]byte, error) {
+	switch algorithm {
+	case "HS256":
+		key, ok := s.Key.([]byte)
+		if !ok || len(key) == 0 {
+			return nil, errors.New("jwt signer [HS256 requires a []byte key]")
+		}
+
+		mac := hmac.New(sha256.New, key)
+		mac.Write(input)
+
+		return mac.Sum(nil), nil
+	case "RS256":
+		key, ok := s.Key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("jwt signer [RS256 requires a *rsa.PrivateKey key]")
+		}
+
+		digest := sha256.Sum256(input)
+
+		return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	}
+
+	return nil, fmt.Errorf("jwt signer [ %s ] is not supported", algorithm)
+}
+
+func jwtSegment(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+func headerOrDefault(header, def string) string {
+	if header == "" {
+		return def
+	}
+
+	return header
+}
+
+func now(clock func() time.Time) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+
+	return clock()
+}
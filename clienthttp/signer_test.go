@@ -0,0 +1,145 @@
+package clienthttp_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientHttp_WithSigner(t *testing.T) {
+	fixedNow := func() time.Time { return time.Unix(1700000000, 0) }
+
+	t.Run("should sign method, path, timestamp and body with HMAC [HMAC]", func(t *testing.T) {
+		var calls int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write([]byte(r.Method + "\n" + r.URL.RequestURI() + "\n" + r.Header.Get("X-Timestamp") + "\n"))
+			mac.Write(body)
+
+			assert.Equal(t, "1700000000", r.Header.Get("X-Timestamp"))
+			assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-Signature"))
+			assert.Equal(t, "key-1", r.Header.Get("X-Key-Id"))
+			assert.Equal(t, `{"id":1,"name":"diego"}`, string(body))
+
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL,
+			clienthttp.WithRetryPolicy(clienthttp.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+			clienthttp.WithSigner(&clienthttp.HMACSigner{Key: []byte("secret"), KeyID: "key-1", Now: fixedNow}),
+		)
+
+		req := clienthttp.NewRequest(http.MethodPut, "/users/1").
+			WithQueryParam("dry-run", "true").
+			WithJSONBody(userFixture{ID: 1, Name: "diego"}).
+			Build()
+
+		_, statusCode, err := client.Do(context.TODO(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should produce the reference SigV4 signature [SIGV4]", func(t *testing.T) {
+		signer := &clienthttp.SigV4Signer{
+			AccessKey: "AKIDEXAMPLE",
+			SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+			Region:    "us-east-1",
+			Service:   "service",
+			Now: func() time.Time {
+				return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+			},
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+
+		err := signer.Sign(req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+		assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+			"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+			req.Header.Get("Authorization"))
+	})
+
+	t.Run("should send a short-lived HS256 JWT [JWT]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			parts := strings.Split(token, ".")
+
+			assert.Len(t, parts, 3)
+
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write([]byte(parts[0] + "." + parts[1]))
+
+			assert.Equal(t, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), parts[2])
+
+			payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+
+			var claims map[string]interface{}
+			assert.NoError(t, json.Unmarshal(payload, &claims))
+			assert.Equal(t, "synergetic", claims["iss"])
+			assert.Equal(t, "partner", claims["aud"])
+			assert.Equal(t, float64(1700000060), claims["exp"])
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithSigner(&clienthttp.JWTSigner{
+			Key:      []byte("secret"),
+			Issuer:   "synergetic",
+			Audience: "partner",
+			Now:      fixedNow,
+		}))
+
+		_, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/users").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+	})
+
+	t.Run("should sign RS256 JWT verifiable with the public key [RS256]", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+
+		token, err := (&clienthttp.JWTSigner{Algorithm: "RS256", Key: key, Subject: "service"}).Token()
+		assert.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+	})
+
+	t.Run("should fail the request when the signer fails [ERROR]", func(t *testing.T) {
+		client := clienthttp.NewClientHTTP(http.DefaultClient, "http://localhost", clienthttp.WithSigner(&clienthttp.HMACSigner{}))
+
+		_, _, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/users").Build())
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "hmac signer [key is empty]")
+	})
+}
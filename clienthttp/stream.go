@@ -0,0 +1,223 @@
+package clienthttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+type BodyTooLargeError struct {
+	Limit int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeded [ %d ] bytes", e.Limit)
+}
+
+func WithMaxBodySize(limit int64) Option {
+	return func(c *clientHttp) {
+		c.maxBodySize = limit
+	}
+}
+
+type limitedBody struct {
+	body      io.ReadCloser
+	limit     int64
+	remaining int64
+}
+
+func (c *clientHttp) limitBody(body io.ReadCloser) io.ReadCloser {
+	if c.maxBodySize <= 0 {
+		return body
+	}
+
+	return &limitedBody{
+		body:      body,
+		limit:     c.maxBodySize,
+		remaining: c.maxBodySize,
+	}
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if l.remaining <= 0 {
+		n, err := l.body.Read(p[:1])
+		if n > 0 {
+			return 0, &BodyTooLargeError{Limit: l.limit}
+		}
+
+		return 0, err
+	}
+
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+
+	n, err := l.body.Read(p)
+	l.remaining -= int64(n)
+
+	return n, err
+}
+
+func (l *limitedBody) Close() error {
+	return l.body.Close()
+}
+
+type StreamResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       io.ReadCloser
+	request    *http.Request
+}
+
+func (c *clientHttp) DoStream(ctx context.Context, req *http.Request) (*StreamResponse, error) {
+	cancel := context.CancelFunc(func() {})
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+
+	resp, req, err := c.send(ctx, req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return &StreamResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       c.limitBody(resp.Body),
+		request:    req,
+	}, nil
+}
+
+func (s *StreamResponse) httpError() error {
+	defer s.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(s.Body, errorBodyLimit))
+
+	return newHTTPError(&response{
+		body:       body,
+		statusCode: s.StatusCode,
+		header:     s.Header,
+		request:    s.request,
+	})
+}
+
+func Stream[T any](ctx context.Context, c StreamClient, builder HTTPRequestBuilder) (*Iterator[T], error) {
+	req, err := builder.BuildE()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.DoStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, resp.httpError()
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return NewNDJSONIterator[T](resp.Body), nil
+	}
+
+	return NewJSONArrayIterator[T](resp.Body), nil
+}
+
+type Iterator[T any] struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+	array   bool
+	started bool
+	done    bool
+	value   T
+	err     error
+}
+
+func NewNDJSONIterator[T any](body io.ReadCloser) *Iterator[T] {
+	return &Iterator[T]{
+		body:    body,
+		decoder: json.NewDecoder(body),
+	}
+}
+
+func NewJSONArrayIterator[T any](body io.ReadCloser) *Iterator[T] {
+	return &Iterator[T]{
+		body:    body,
+		decoder: json.NewDecoder(body),
+		array:   true,
+	}
+}
+
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+
+	if it.array && !it.started {
+		it.started = true
+
+		if err := it.expectDelim('['); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	if !it.decoder.More() {
+		if it.array {
+			it.err = it.expectDelim(']')
+		}
+
+		it.done = true
+
+		return false
+	}
+
+	var value T
+
+	if err := it.decoder.Decode(&value); err != nil {
+		it.err = err
+		return false
+	}
+
+	it.value = value
+
+	return true
+}
+
+func (it *Iterator[T]) expectDelim(expected json.Delim) error {
+	token, err := it.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf("json stream expected [ %v ] got [ %v ]", expected, token)
+	}
+
+	return nil
+}
+
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+func (it *Iterator[T]) Close() error {
+	return it.body.Close()
+}
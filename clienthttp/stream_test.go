@@ -0,0 +1,152 @@
+package clienthttp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientHttp_DoStream(t *testing.T) {
+	t.Run("should hand the body as a stream [SUCCESS]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, strings.Repeat("a", 10000))
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL)
+
+		resp, err := client.(clienthttp.StreamClient).DoStream(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/export").Build())
+		assert.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, body, 10000)
+	})
+
+	t.Run("should abort when the body exceeds the max size [TOO LARGE]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, strings.Repeat("a", 10000))
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithMaxBodySize(1000))
+
+		resp, err := client.(clienthttp.StreamClient).DoStream(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/export").Build())
+		assert.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		_, err = io.ReadAll(resp.Body)
+
+		var tooLarge *clienthttp.BodyTooLargeError
+
+		assert.True(t, errors.As(err, &tooLarge))
+		assert.Equal(t, int64(1000), tooLarge.Limit)
+
+		_, _, err = client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/export").Build())
+
+		assert.True(t, errors.As(err, &tooLarge))
+	})
+
+	t.Run("should allow bodies of exactly the max size [LIMIT]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, strings.Repeat("a", 1000))
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL, clienthttp.WithMaxBodySize(1000))
+
+		body, _, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/export").Build())
+
+		assert.NoError(t, err)
+		assert.Len(t, body, 1000)
+	})
+}
+
+func TestStream(t *testing.T) {
+	t.Run("should iterate NDJSON elements [NDJSON]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+
+			for i := 1; i <= 3; i++ {
+				fmt.Fprintf(w, "{\"id\":%d}\n", i)
+			}
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.StreamClient)
+
+		it, err := clienthttp.Stream[userFixture](context.TODO(), client, clienthttp.NewRequest(http.MethodGet, "/export"))
+		assert.NoError(t, err)
+
+		defer it.Close()
+
+		var ids []int
+		for it.Next() {
+			ids = append(ids, it.Value().ID)
+		}
+
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []int{1, 2, 3}, ids)
+	})
+
+	t.Run("should iterate JSON array elements [ARRAY]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			fmt.Fprint(w, `[{"id":1},{"id":2}]`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.StreamClient)
+
+		it, err := clienthttp.Stream[userFixture](context.TODO(), client, clienthttp.NewRequest(http.MethodGet, "/export"))
+		assert.NoError(t, err)
+
+		defer it.Close()
+
+		var ids []int
+		for it.Next() {
+			ids = append(ids, it.Value().ID)
+		}
+
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []int{1, 2}, ids)
+	})
+
+	t.Run("should report malformed arrays [INVALID]", func(t *testing.T) {
+		it := clienthttp.NewJSONArrayIterator[userFixture](io.NopCloser(strings.NewReader(`{"id":1}`)))
+
+		assert.False(t, it.Next())
+		assert.Error(t, it.Err())
+	})
+
+	t.Run("should return HTTPError on unsuccessful status [ERROR]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `forbidden`)
+		}))
+		defer ts.Close()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL).(clienthttp.StreamClient)
+
+		_, err := clienthttp.Stream[userFixture](context.TODO(), client, clienthttp.NewRequest(http.MethodGet, "/export"))
+
+		var httpErr *clienthttp.HTTPError
+
+		assert.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, "forbidden", string(httpErr.Body))
+	})
+}
@@ -0,0 +1,82 @@
+package clienthttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func WithTelemetry(conf telemetry.Config) Option {
+	return func(c *clientHttp) {
+		c.telemetry = &conf
+	}
+}
+
+func telemetryMiddleware(conf telemetry.Config) Middleware {
+	tracer := conf.Tracer("clienthttp")
+	instruments := telemetry.NewInstruments(conf.Meter("clienthttp"), "http.client.request")
+	propagator := conf.Propagation()
+
+	return func(next ClientAPI) ClientAPI {
+		return ClientAPIFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+
+			attrs := []attribute.KeyValue{
+				semconv.HTTPRequestMethodKey.String(req.Method),
+				semconv.ServerAddress(req.URL.Hostname()),
+			}
+
+			if port, err := strconv.Atoi(req.URL.Port()); err == nil {
+				attrs = append(attrs, semconv.ServerPort(port))
+			}
+
+			ctx, span := tracer.Start(req.Context(), req.Method,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+				trace.WithAttributes(semconv.URLFull(req.URL.Redacted())),
+			)
+
+			tracedReq := req.Clone(ctx)
+			propagator.Inject(ctx, propagation.HeaderCarrier(tracedReq.Header))
+
+			resp, err := next.Do(tracedReq)
+			if err != nil {
+				telemetry.End(span, err)
+				instruments.Record(ctx, start, err, append(attrs, attribute.String("error.type", errorType(err)))...)
+
+				return nil, err
+			}
+
+			status := semconv.HTTPResponseStatusCode(resp.StatusCode)
+			span.SetAttributes(status)
+			attrs = append(attrs, status)
+
+			var failure error
+			if resp.StatusCode >= http.StatusBadRequest {
+				failure = &HTTPError{StatusCode: resp.StatusCode, Method: req.Method, URL: req.URL.Redacted()}
+				attrs = append(attrs, attribute.String("error.type", strconv.Itoa(resp.StatusCode)))
+			}
+
+			telemetry.End(span, failure)
+			instruments.Record(ctx, start, failure, attrs...)
+
+			return resp, nil
+		})
+	}
+}
+
+func errorType(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+
+	return "_OTHER"
+}
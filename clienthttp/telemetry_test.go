@@ -0,0 +1,64 @@
+package clienthttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/dot-backend/synergetic-craft/telemetry/telemetrytest"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestClientHttp_WithTelemetry(t *testing.T) {
+	t.Run("should create a client span per attempt and propagate W3C trace context", func(t *testing.T) {
+		var traceparents []string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparents = append(traceparents, r.Header.Get("Traceparent"))
+			if len(traceparents) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer ts.Close()
+
+		recorder := telemetrytest.New()
+
+		client := clienthttp.NewClientHTTP(http.DefaultClient, ts.URL,
+			clienthttp.WithTelemetry(recorder.Config),
+			clienthttp.WithRetryPolicy(clienthttp.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+		)
+
+		_, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/users").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+
+		spans := recorder.Spans()
+		assert.Len(t, spans, 2)
+
+		for i, span := range spans {
+			assert.Equal(t, http.MethodGet, span.Name)
+			assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+			assert.Contains(t, traceparents[i], span.SpanContext.SpanID().String())
+		}
+
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Contains(t, spans[0].Attributes, semconv.HTTPResponseStatusCode(http.StatusServiceUnavailable))
+		assert.Equal(t, codes.Unset, spans[1].Status.Code)
+
+		duration, ok := recorder.Metric("http.client.request.duration")
+		assert.True(t, ok)
+		assert.Len(t, duration.Data.(metricdata.Histogram[float64]).DataPoints, 2)
+
+		errs, ok := recorder.Metric("http.client.request.errors")
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), errs.Data.(metricdata.Sum[int64]).DataPoints[0].Value)
+	})
+}
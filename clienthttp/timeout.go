@@ -0,0 +1,60 @@
+package clienthttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *clientHttp) {
+		c.timeout = timeout
+	}
+}
+
+func WithAttemptTimeout(timeout time.Duration) Option {
+	return func(c *clientHttp) {
+		c.attemptTimeout = timeout
+	}
+}
+
+type attemptTimeoutClient struct {
+	next    ClientAPI
+	timeout time.Duration
+}
+
+func newAttemptTimeoutClient(next ClientAPI, timeout time.Duration) ClientAPI {
+	return &attemptTimeoutClient{
+		next:    next,
+		timeout: timeout,
+	}
+}
+
+func (c *attemptTimeoutClient) Do(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), c.timeout)
+
+	resp, err := c.next.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// cancelBody releases the context backing a response once the caller is done
+// reading it, so deadlines keep bounding the body and not just the headers.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
@@ -0,0 +1,247 @@
+package clienthttp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	defaultDialTimeout           = 5 * time.Second
+	defaultKeepAlive             = 30 * time.Second
+	defaultTLSHandshakeTimeout   = 5 * time.Second
+	defaultIdleConnTimeout       = 90 * time.Second
+	defaultExpectContinueTimeout = time.Second
+	defaultMaxIdleConns          = 100
+	defaultMaxIdleConnsPerHost   = 10
+	defaultMaxRedirects          = 10
+)
+
+// TransportConfig tunes the http.Client built by NewHTTPClient. Zero values
+// fall back to defaults suited to service-to-service calls rather than to
+// net/http's, which keep only two idle connections per host.
+type TransportConfig struct {
+	// Timeout bounds a whole exchange, body included; zero means no limit.
+	Timeout               time.Duration
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	IdleConnTimeout       time.Duration
+	ExpectContinueTimeout time.Duration
+
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost is unlimited when zero.
+	MaxConnsPerHost int
+
+	DisableHTTP2       bool
+	DisableCompression bool
+
+	// ProxyURL routes every request through the proxy; when empty the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables apply unless DisableProxy.
+	ProxyURL     string
+	DisableProxy bool
+
+	TLS       TLSConfig
+	Redirects RedirectPolicy
+}
+
+// TLSConfig adds CA bundles to the system roots and, for mTLS, a client
+// certificate. Files and PEM contents can be combined.
+type TLSConfig struct {
+	CAFiles []string
+	CAPEM   []byte
+
+	CertFile string
+	KeyFile  string
+	CertPEM  []byte
+	KeyPEM   []byte
+
+	ServerName string
+	// MinVersion defaults to TLS 1.2.
+	MinVersion         uint16
+	InsecureSkipVerify bool
+}
+
+type RedirectPolicy struct {
+	// Disabled returns redirect responses to the caller as they are.
+	Disabled bool
+	// Max defaults to 10, as in net/http.
+	Max int
+	// SameHost refuses to follow redirects to another host.
+	SameHost bool
+}
+
+var ErrRedirectNotAllowed = errors.New("redirect policy [redirect not allowed]")
+
+// NewClientHTTPWithConfig builds the http.Client from conf and wraps it with
+// NewClientHTTP.
+func NewClientHTTPWithConfig(domain string, conf TransportConfig, opts ...Option) (ClientHTTP, error) {
+	client, err := NewHTTPClient(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientHTTP(client, domain, opts...), nil
+}
+
+func NewHTTPClient(conf TransportConfig) (*http.Client, error) {
+	transport, err := NewTransport(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       conf.Timeout,
+		CheckRedirect: conf.Redirects.check,
+	}, nil
+}
+
+func NewTransport(conf TransportConfig) (*http.Transport, error) {
+	tlsConfig, err := conf.TLS.build()
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := conf.proxy()
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   orDefault(conf.DialTimeout, defaultDialTimeout),
+		KeepAlive: orDefault(conf.KeepAlive, defaultKeepAlive),
+	}
+
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   orDefault(conf.TLSHandshakeTimeout, defaultTLSHandshakeTimeout),
+		ResponseHeaderTimeout: conf.ResponseHeaderTimeout,
+		IdleConnTimeout:       orDefault(conf.IdleConnTimeout, defaultIdleConnTimeout),
+		ExpectContinueTimeout: orDefault(conf.ExpectContinueTimeout, defaultExpectContinueTimeout),
+		MaxIdleConns:          orDefault(conf.MaxIdleConns, defaultMaxIdleConns),
+		MaxIdleConnsPerHost:   orDefault(conf.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:       conf.MaxConnsPerHost,
+		DisableCompression:    conf.DisableCompression,
+		ForceAttemptHTTP2:     !conf.DisableHTTP2,
+	}
+
+	if conf.DisableHTTP2 {
+		// A non-nil empty map is how net/http is told not to negotiate h2.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return transport, nil
+}
+
+func (c TransportConfig) proxy() (func(*http.Request) (*url.URL, error), error) {
+	if c.DisableProxy {
+		return nil, nil
+	}
+
+	if c.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(c.ProxyURL)
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("transport config [ invalid proxy url %q ]", c.ProxyURL)
+	}
+
+	return http.ProxyURL(proxyURL), nil
+}
+
+func (c TLSConfig) build() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         c.MinVersion,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+
+	if len(c.CAFiles) > 0 || len(c.CAPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		bundles := [][]byte{c.CAPEM}
+		for _, file := range c.CAFiles {
+			bundle, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("transport config [ CA bundle %s ]: %w", file, err)
+			}
+
+			bundles = append(bundles, bundle)
+		}
+
+		for _, bundle := range bundles {
+			if len(bundle) > 0 && !pool.AppendCertsFromPEM(bundle) {
+				return nil, errors.New("transport config [ CA bundle has no valid certificates ]")
+			}
+		}
+
+		config.RootCAs = pool
+	}
+
+	certPEM, keyPEM := c.CertPEM, c.KeyPEM
+	if c.CertFile != "" || c.KeyFile != "" {
+		var err error
+		if certPEM, err = os.ReadFile(c.CertFile); err != nil {
+			return nil, fmt.Errorf("transport config [ client certificate ]: %w", err)
+		}
+
+		if keyPEM, err = os.ReadFile(c.KeyFile); err != nil {
+			return nil, fmt.Errorf("transport config [ client key ]: %w", err)
+		}
+	}
+
+	if len(certPEM) > 0 || len(keyPEM) > 0 {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("transport config [ client certificate ]: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+func (p RedirectPolicy) check(req *http.Request, via []*http.Request) error {
+	if p.Disabled {
+		return http.ErrUseLastResponse
+	}
+
+	if len(via) >= orDefault(p.Max, defaultMaxRedirects) {
+		return fmt.Errorf("%w: stopped after %d redirects", ErrRedirectNotAllowed, len(via))
+	}
+
+	if p.SameHost && req.URL.Host != via[0].URL.Host {
+		return fmt.Errorf("%w: %s is on another host", ErrRedirectNotAllowed, req.URL)
+	}
+
+	return nil
+}
+
+func orDefault[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
+
+	return value
+}
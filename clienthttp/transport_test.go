@@ -0,0 +1,189 @@
+package clienthttp_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/clienthttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClientCertificate(t *testing.T) (certPEM, keyPEM []byte, pool *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "unit-test-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		pool
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("should tune the transport with sensible defaults [DEFAULTS]", func(t *testing.T) {
+		transport, err := clienthttp.NewTransport(clienthttp.TransportConfig{MaxConnsPerHost: 20})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, transport.MaxIdleConnsPerHost)
+		assert.Equal(t, 20, transport.MaxConnsPerHost)
+		assert.Equal(t, 90*time.Second, transport.IdleConnTimeout)
+		assert.True(t, transport.ForceAttemptHTTP2)
+		assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+	})
+
+	t.Run("should authenticate with mTLS over HTTP/2 using a CA bundle [MTLS]", func(t *testing.T) {
+		certPEM, keyPEM, clientCAs := newClientCertificate(t)
+
+		ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %s", r.Proto, r.TLS.PeerCertificates[0].Subject.CommonName)
+		}))
+		ts.EnableHTTP2 = true
+		ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+		ts.StartTLS()
+		defer ts.Close()
+
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		assert.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0o600))
+
+		client, err := clienthttp.NewClientHTTPWithConfig(ts.URL, clienthttp.TransportConfig{
+			Timeout: time.Second,
+			TLS: clienthttp.TLSConfig{
+				CAFiles: []string{caFile},
+				CertPEM: certPEM,
+				KeyPEM:  keyPEM,
+			},
+		})
+		assert.NoError(t, err)
+
+		body, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/secure").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, "HTTP/2.0 unit-test-client", string(body))
+	})
+
+	t.Run("should fail without the server CA [CA]", func(t *testing.T) {
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer ts.Close()
+
+		client, err := clienthttp.NewClientHTTPWithConfig(ts.URL, clienthttp.TransportConfig{})
+		assert.NoError(t, err)
+
+		_, _, err = client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/").Build())
+
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject invalid certificates in the config [INVALID]", func(t *testing.T) {
+		_, err := clienthttp.NewHTTPClient(clienthttp.TransportConfig{TLS: clienthttp.TLSConfig{CAPEM: []byte("not a certificate")}})
+		assert.Error(t, err)
+
+		_, err = clienthttp.NewHTTPClient(clienthttp.TransportConfig{TLS: clienthttp.TLSConfig{CertPEM: []byte("cert"), KeyPEM: []byte("key")}})
+		assert.Error(t, err)
+
+		_, err = clienthttp.NewHTTPClient(clienthttp.TransportConfig{ProxyURL: "::"})
+		assert.Error(t, err)
+	})
+
+	t.Run("should send requests through the configured proxy [PROXY]", func(t *testing.T) {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "proxied %s", r.URL)
+		}))
+		defer proxy.Close()
+
+		client, err := clienthttp.NewClientHTTPWithConfig("http://upstream.internal", clienthttp.TransportConfig{ProxyURL: proxy.URL})
+		assert.NoError(t, err)
+
+		body, _, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/users").Build())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "proxied http://upstream.internal/users", string(body))
+	})
+
+	t.Run("should apply the redirect policy [REDIRECT]", func(t *testing.T) {
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer other.Close()
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/local":
+				http.Redirect(w, r, "/target", http.StatusFound)
+			case "/remote":
+				http.Redirect(w, r, other.URL, http.StatusFound)
+			default:
+				fmt.Fprint(w, "target")
+			}
+		}))
+		defer ts.Close()
+
+		client, err := clienthttp.NewClientHTTPWithConfig(ts.URL, clienthttp.TransportConfig{Redirects: clienthttp.RedirectPolicy{SameHost: true}})
+		assert.NoError(t, err)
+
+		body, _, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/local").Build())
+		assert.NoError(t, err)
+		assert.Equal(t, "target", string(body))
+
+		_, _, err = client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/remote").Build())
+		assert.ErrorIs(t, err, clienthttp.ErrRedirectNotAllowed)
+
+		client, err = clienthttp.NewClientHTTPWithConfig(ts.URL, clienthttp.TransportConfig{Redirects: clienthttp.RedirectPolicy{Disabled: true}})
+		assert.NoError(t, err)
+
+		_, statusCode, err := client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/local").Build())
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusFound, statusCode)
+	})
+
+	t.Run("should bound the exchange with the timeout [TIMEOUT]", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		defer ts.Close()
+
+		client, err := clienthttp.NewClientHTTPWithConfig(ts.URL, clienthttp.TransportConfig{Timeout: 50 * time.Millisecond})
+		assert.NoError(t, err)
+
+		_, _, err = client.Do(context.TODO(), clienthttp.NewRequest(http.MethodGet, "/slow").Build())
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Client.Timeout exceeded")
+	})
+}
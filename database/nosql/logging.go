@@ -0,0 +1,60 @@
+package nosql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dot-backend/synergetic-craft/logger"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func WithLogger(log logger.Logger) Option {
+	return func(opts *options.ClientOptions) {
+		opts.SetMonitor(chainMonitors(opts.Monitor, &event.CommandMonitor{
+			Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+				log.Log(ctx, logger.LevelDebug, "mongo command [done]", commandFields(evt.CommandFinishedEvent)...)
+			},
+			Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+				fields := append(commandFields(evt.CommandFinishedEvent), logger.Err(errors.New(evt.Failure)))
+				log.Log(ctx, logger.LevelError, "mongo command [failed]", fields...)
+			},
+		}))
+	}
+}
+
+func commandFields(evt event.CommandFinishedEvent) []logger.Field {
+	return []logger.Field{
+		logger.Any("command", evt.CommandName),
+		logger.Any("database", evt.DatabaseName),
+		logger.Latency(evt.Duration),
+	}
+}
+
+// chainMonitors lets logging and telemetry share the single command monitor
+// slot the driver exposes.
+func chainMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				if m != nil && m.Started != nil {
+					m.Started(ctx, evt)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				if m != nil && m.Succeeded != nil {
+					m.Succeeded(ctx, evt)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				if m != nil && m.Failed != nil {
+					m.Failed(ctx, evt)
+				}
+			}
+		},
+	}
+}
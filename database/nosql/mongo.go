@@ -21,13 +21,15 @@ type DocConfig struct {
 	ConnectionStr string
 }
 
+type Option func(*options.ClientOptions)
+
 type mongoDB struct {
 	docDBName string
 	client    *mongo.Client
 }
 
-func NewMongo(conf DocConfig) (*mongoDB, error) {
-	client, err := docDBConnect(conf)
+func NewMongo(conf DocConfig, opts ...Option) (*mongoDB, error) {
+	client, err := docDBConnect(conf, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,14 +40,18 @@ func NewMongo(conf DocConfig) (*mongoDB, error) {
 	}, nil
 }
 
-func docDBConnect(conf DocConfig) (*mongo.Client, error) {
+func docDBConnect(conf DocConfig, opts ...Option) (*mongo.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 
-	options := options.Client()
-	options.ApplyURI(conf.ConnectionStr)
+	clientOptions := options.Client()
+	clientOptions.ApplyURI(conf.ConnectionStr)
+
+	for _, opt := range opts {
+		opt(clientOptions)
+	}
 
-	client, err := mongo.Connect(ctx, options)
+	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, err
 	}
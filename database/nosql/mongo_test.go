@@ -5,8 +5,8 @@ package nosql_test
 
 import (
 	"context"
+	"github.com/dot-backend/synergetic-craft/database/nosql"
 	"github.com/stretchr/testify/assert"
-	"synergetic-craft/database/nosql"
 	"testing"
 )
 
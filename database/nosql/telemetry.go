@@ -0,0 +1,125 @@
+package nosql
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/telemetry"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultMaxPoolSize = 100
+
+func WithTelemetry(conf telemetry.Config) Option {
+	return func(opts *options.ClientOptions) {
+		meter := conf.Meter("database/nosql")
+		monitor := newTelemetryMonitor(conf)
+
+		opts.SetMonitor(chainMonitors(opts.Monitor, monitor.commandMonitor()))
+		opts.SetPoolMonitor(monitor.poolMonitor())
+
+		maxPoolSize := int64(defaultMaxPoolSize)
+		if opts.MaxPoolSize != nil {
+			maxPoolSize = int64(*opts.MaxPoolSize)
+		}
+
+		telemetry.RegisterPoolGauges(meter, "mongodb", func() telemetry.PoolStats {
+			used := atomic.LoadInt64(&monitor.checkedOut)
+
+			return telemetry.PoolStats{
+				Used: used,
+				Idle: atomic.LoadInt64(&monitor.open) - used,
+				Max:  maxPoolSize,
+			}
+		})
+	}
+}
+
+type telemetryMonitor struct {
+	tracer      trace.Tracer
+	instruments telemetry.Instruments
+	spans       sync.Map
+
+	open       int64
+	checkedOut int64
+}
+
+type commandSpan struct {
+	ctx   context.Context
+	span  trace.Span
+	start time.Time
+	attrs []attribute.KeyValue
+}
+
+func newTelemetryMonitor(conf telemetry.Config) *telemetryMonitor {
+	return &telemetryMonitor{
+		tracer:      conf.Tracer("database/nosql"),
+		instruments: telemetry.NewInstruments(conf.Meter("database/nosql"), "db.client.operation"),
+	}
+}
+
+func (m *telemetryMonitor) commandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: m.started,
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			m.finished(evt.RequestID, nil)
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			m.finished(evt.RequestID, errors.New(evt.Failure))
+		},
+	}
+}
+
+func (m *telemetryMonitor) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemMongoDB,
+		semconv.DBName(evt.DatabaseName),
+		semconv.DBOperation(evt.CommandName),
+	}
+
+	name := evt.CommandName
+	if collection, ok := evt.Command.Lookup(evt.CommandName).StringValueOK(); ok {
+		attrs = append(attrs, semconv.DBMongoDBCollection(collection))
+		name += " " + collection
+	}
+
+	ctx, span := m.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+	m.spans.Store(evt.RequestID, &commandSpan{ctx: ctx, span: span, start: time.Now(), attrs: attrs})
+}
+
+func (m *telemetryMonitor) finished(requestID int64, err error) {
+	value, ok := m.spans.LoadAndDelete(requestID)
+	if !ok {
+		return
+	}
+
+	command := value.(*commandSpan)
+
+	m.instruments.Record(command.ctx, command.start, err, command.attrs...)
+	telemetry.End(command.span, err)
+}
+
+func (m *telemetryMonitor) poolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			switch evt.Type {
+			case event.ConnectionCreated:
+				atomic.AddInt64(&m.open, 1)
+			case event.ConnectionClosed:
+				atomic.AddInt64(&m.open, -1)
+			case event.GetSucceeded:
+				atomic.AddInt64(&m.checkedOut, 1)
+			case event.ConnectionReturned:
+				atomic.AddInt64(&m.checkedOut, -1)
+			}
+		},
+	}
+}
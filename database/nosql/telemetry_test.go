@@ -0,0 +1,55 @@
+package nosql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dot-backend/synergetic-craft/telemetry/telemetrytest"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+func TestTelemetryMonitor(t *testing.T) {
+	t.Run("should trace commands with their collection", func(t *testing.T) {
+		recorder := telemetrytest.New()
+		monitor := newTelemetryMonitor(recorder.Config).commandMonitor()
+
+		command, _ := bson.Marshal(bson.D{{Key: "insert", Value: "users"}})
+
+		monitor.Started(context.Background(), &event.CommandStartedEvent{
+			Command: command, DatabaseName: "app", CommandName: "insert", RequestID: 1,
+		})
+		monitor.Failed(context.Background(), &event.CommandFailedEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "insert", RequestID: 1},
+			Failure:              "duplicate key",
+		})
+
+		spans := recorder.Spans()
+
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "insert users", spans[0].Name)
+		assert.Contains(t, spans[0].Attributes, semconv.DBMongoDBCollection("users"))
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+
+		errs, ok := recorder.Metric("db.client.operation.errors")
+
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), errs.Data.(metricdata.Sum[int64]).DataPoints[0].Value)
+	})
+
+	t.Run("should track checked out connections", func(t *testing.T) {
+		monitor := newTelemetryMonitor(telemetrytest.New().Config)
+		pool := monitor.poolMonitor()
+
+		for _, eventType := range []string{event.ConnectionCreated, event.ConnectionCreated, event.GetSucceeded} {
+			pool.Event(&event.PoolEvent{Type: eventType})
+		}
+
+		assert.Equal(t, int64(2), monitor.open)
+		assert.Equal(t, int64(1), monitor.checkedOut)
+	})
+}
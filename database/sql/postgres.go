@@ -1,10 +1,13 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/dot-backend/synergetic-craft/logger"
 	"github.com/golang-migrate/migrate/v4"
 	pg "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -22,16 +25,27 @@ type PGConfig struct {
 	PoolSize int
 }
 
+type Option func(*postgres)
+
 type postgres struct {
 	connection *sql.DB
 	config     PGConfig
+	telemetry  *postgresTelemetry
+	log        logger.Logger
 }
 
-func NewPostgres(conf PGConfig) Postgres {
-	return &postgres{
+func NewPostgres(conf PGConfig, opts ...Option) Postgres {
+	p := &postgres{
 		connection: newConnection(conf),
 		config:     conf,
+		log:        logger.Nop(),
+	}
+
+	for _, opt := range opts {
+		opt(p)
 	}
+
+	return p
 }
 
 func newConnection(conf PGConfig) *sql.DB {
@@ -45,7 +59,28 @@ func newConnection(conf PGConfig) *sql.DB {
 	return conn
 }
 
-func (p *postgres) Migrate(file string) error {
+func WithLogger(log logger.Logger) Option {
+	return func(p *postgres) {
+		p.log = log
+	}
+}
+
+func (p *postgres) Migrate(file string) (err error) {
+	start := time.Now()
+	finish := p.telemetry.start("migrate")
+
+	defer func() {
+		finish(err)
+
+		fields := []logger.Field{logger.Any("database", p.config.Database), logger.Any("source", file), logger.Latency(time.Since(start))}
+		if err != nil {
+			p.log.Log(context.Background(), logger.LevelError, "postgres migrations [failed]", append(fields, logger.Err(err))...)
+			return
+		}
+
+		p.log.Log(context.Background(), logger.LevelInfo, "postgres migrations [applied]", fields...)
+	}()
+
 	driver, err := pg.WithInstance(p.connection, &pg.Config{})
 	if err != nil {
 		return fmt.Errorf("error creating driver instance: %+v", err)
@@ -6,7 +6,7 @@ package sql_test
 import (
 	"testing"
 
-	"synergetic-craft/database/sql"
+	"github.com/dot-backend/synergetic-craft/database/sql"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -0,0 +1,58 @@
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type postgresTelemetry struct {
+	tracer      trace.Tracer
+	instruments telemetry.Instruments
+	attrs       []attribute.KeyValue
+}
+
+func WithTelemetry(conf telemetry.Config) Option {
+	return func(p *postgres) {
+		meter := conf.Meter("database/sql")
+
+		p.telemetry = &postgresTelemetry{
+			tracer:      conf.Tracer("database/sql"),
+			instruments: telemetry.NewInstruments(meter, "db.client.operation"),
+			attrs:       []attribute.KeyValue{semconv.DBSystemPostgreSQL, semconv.DBName(p.config.Database)},
+		}
+
+		telemetry.RegisterPoolGauges(meter, p.config.Database, func() telemetry.PoolStats {
+			stats := p.connection.Stats()
+
+			return telemetry.PoolStats{
+				Used: int64(stats.InUse),
+				Idle: int64(stats.Idle),
+				Max:  int64(stats.MaxOpenConnections),
+			}
+		})
+	}
+}
+
+func (t *postgresTelemetry) start(operation string) func(err error) {
+	if t == nil {
+		return func(error) {}
+	}
+
+	start := time.Now()
+	attrs := append(t.attrs[:len(t.attrs):len(t.attrs)], semconv.DBOperation(operation))
+
+	ctx, span := t.tracer.Start(context.Background(), operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	return func(err error) {
+		t.instruments.Record(ctx, start, err, attrs...)
+		telemetry.End(span, err)
+	}
+}
@@ -1,11 +1,14 @@
 package consumer
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
-	"github.com/labstack/gommon/log"
+	"github.com/dot-backend/synergetic-craft/logger"
 )
 
 type Consumer interface {
@@ -15,6 +18,8 @@ type Consumer interface {
 	SetHandlers(handlers map[string]func([]byte) error)
 }
 
+type Option func(*consumer)
+
 type consumer struct {
 	topic         string
 	groupID       string
@@ -23,20 +28,45 @@ type consumer struct {
 	enableLogging bool
 	isRunning     bool
 	consumer      *kafka.Consumer
+	telemetry     *consumerTelemetry
+	log           logger.Logger
+
+	mu          sync.Mutex
+	done        chan struct{}
+	dispatching bool
 }
 
 type message struct {
 	Name string `json:"name"`
 }
 
-func NewConsumer(broker string, groupID, topic string, enableLogging bool) Consumer {
-	return &consumer{
+func NewConsumer(broker string, groupID, topic string, enableLogging bool, opts ...Option) Consumer {
+	c := &consumer{
 		topic:         topic,
 		groupID:       groupID,
 		broker:        broker,
 		enableLogging: enableLogging,
 		isRunning:     true,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.log == nil {
+		c.log = logger.Nop()
+		if enableLogging {
+			c.log = logger.NewSlog(nil)
+		}
+	}
+
+	return c
+}
+
+func WithLogger(log logger.Logger) Option {
+	return func(c *consumer) {
+		c.log = log
+	}
 }
 
 func (kc *consumer) Connect() error {
@@ -63,19 +93,26 @@ func (kc *consumer) Connect() error {
 }
 
 func (kc *consumer) EventProcessor() {
-	defer kc.Stop()
+	done := make(chan struct{})
 
-	for kc.isRunning {
+	kc.mu.Lock()
+	kc.done = done
+	kc.mu.Unlock()
+
+	defer func() {
+		kc.close()
+		close(done)
+	}()
+
+	for kc.running() {
 		event := kc.consumer.Poll(100)
 		if event == nil {
 			continue
 		}
 
-		err := kc.event(event)
-
-		if kc.enableLogging {
-			log.Info(err)
-		}
+		kc.setDispatching(true)
+		_ = kc.event(event)
+		kc.setDispatching(false)
 	}
 }
 
@@ -83,31 +120,102 @@ func (kc *consumer) SetHandlers(handlers map[string]func([]byte) error) {
 	kc.handlers = handlers
 }
 
+// Stop asks a running EventProcessor to leave its poll loop and waits for it
+// to close the consumer, since closing it under an in-flight Poll crashes
+// librdkafka. While a handler runs, which may be the caller, Stop returns
+// right away and the loop closes the consumer once the handler is done.
 func (kc *consumer) Stop() {
+	kc.mu.Lock()
+	kc.isRunning = false
+	done, dispatching := kc.done, kc.dispatching
+	kc.mu.Unlock()
+
+	if done == nil {
+		kc.close()
+		return
+	}
+
+	if !dispatching {
+		<-done
+	}
+}
+
+func (kc *consumer) setDispatching(dispatching bool) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	kc.dispatching = dispatching
+}
+
+func (kc *consumer) running() bool {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	return kc.isRunning
+}
+
+func (kc *consumer) close() {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
 	if kc.consumer != nil {
 		_ = kc.consumer.Close()
+		kc.consumer = nil
 	}
-
-	kc.isRunning = false
 }
 
 func (kc *consumer) event(event kafka.Event) (errEvent error) {
 	switch ev := event.(type) {
 	case *kafka.Message:
-		var msg message
+		start := time.Now()
+		finish := kc.telemetry.start(ev, kc.groupID)
 
-		if err := json.Unmarshal(ev.Value, &msg); err != nil {
-			return err
-		}
-
-		if _, ok := kc.handlers[msg.Name]; !ok {
-			return fmt.Errorf("event handler [ %s ] does not exist", msg.Name)
-		}
+		err := kc.message(ev)
+		finish(err)
+		kc.logMessage(ev, time.Since(start), err)
 
-		return kc.handlers[msg.Name](ev.Value)
+		return err
 	case kafka.Error:
-		return fmt.Errorf("error code [ %v ]\nevent [ %v ]", ev.Code(), ev)
+		err := fmt.Errorf("error code [ %v ]\nevent [ %v ]", ev.Code(), ev)
+		kc.log.Log(context.Background(), logger.LevelError, "kafka consumer [error event]", logger.Err(err))
+
+		return err
 	}
 
 	return nil
 }
+
+func (kc *consumer) message(ev *kafka.Message) error {
+	var msg message
+
+	if err := json.Unmarshal(ev.Value, &msg); err != nil {
+		return err
+	}
+
+	if _, ok := kc.handlers[msg.Name]; !ok {
+		return fmt.Errorf("event handler [ %s ] does not exist", msg.Name)
+	}
+
+	return kc.handlers[msg.Name](ev.Value)
+}
+
+func (kc *consumer) logMessage(ev *kafka.Message, latency time.Duration, err error) {
+	var topic string
+	if ev.TopicPartition.Topic != nil {
+		topic = *ev.TopicPartition.Topic
+	}
+
+	fields := []logger.Field{
+		logger.Topic(topic),
+		logger.Partition(ev.TopicPartition.Partition),
+		logger.Offset(int64(ev.TopicPartition.Offset)),
+		logger.Latency(latency),
+	}
+
+	if err != nil {
+		kc.log.Log(context.Background(), logger.LevelError, "kafka message [processing failed]", append(fields, logger.Err(err))...)
+		return
+	}
+
+	kc.log.Log(context.Background(), logger.LevelDebug, "kafka message [processed]", append(fields, logger.Payload(ev.Value))...)
+}
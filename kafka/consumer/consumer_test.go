@@ -1,12 +1,19 @@
 package consumer_test
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/dot-backend/synergetic-craft/kafka/consumer"
+	"github.com/dot-backend/synergetic-craft/kafka/producer"
+	"github.com/dot-backend/synergetic-craft/logger"
+	"github.com/dot-backend/synergetic-craft/telemetry/telemetrytest"
 	"github.com/labstack/gommon/log"
 	"github.com/stretchr/testify/assert"
-	"synergetic-craft/kafka/consumer"
-	"synergetic-craft/kafka/producer"
+	"log/slog"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -89,3 +96,191 @@ func TestConsumer_EventProcessor(t *testing.T) {
 		c.Stop()
 	})
 }
+
+func TestConsumer_Stop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should stop without blocking when called from a handler", func(t *testing.T) {
+		mockProducer, _ := kafka.NewMockCluster(1)
+		defer mockProducer.Close()
+
+		broker := mockProducer.BootstrapServers()
+
+		c := consumer.NewConsumer(broker, "group", "stop", false)
+		if err := c.Connect(); err != nil {
+			t.Fatal(ErrConnectFixture)
+		}
+
+		c.SetHandlers(map[string]func([]byte) error{
+			"stop event": func([]byte) error {
+				c.Stop()
+				return nil
+			},
+		})
+
+		p, _ := producer.NewProducer(broker, 2000)
+		_ = p.Connect()
+		defer p.Close()
+
+		assert.NoError(t, <-p.Send("stop", []byte(`key`), []byte(`{"name":"stop event"}`)))
+
+		stopped := make(chan struct{})
+		go func() {
+			c.EventProcessor()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(15 * time.Second):
+			t.Fatal("EventProcessor did not return after Stop was called from a handler")
+		}
+
+		c.Stop()
+	})
+
+	t.Run("should wait for EventProcessor to close the consumer when called from another goroutine", func(t *testing.T) {
+		mockProducer, _ := kafka.NewMockCluster(1)
+		defer mockProducer.Close()
+
+		c := consumer.NewConsumer(mockProducer.BootstrapServers(), "group", "polling", false)
+		if err := c.Connect(); err != nil {
+			t.Fatal(ErrConnectFixture)
+		}
+
+		stopped := make(chan struct{})
+		go func() {
+			c.EventProcessor()
+			close(stopped)
+		}()
+
+		time.Sleep(500 * time.Millisecond)
+
+		c.Stop()
+
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("Stop returned before EventProcessor closed the consumer")
+		}
+
+		assert.NotPanics(t, c.Stop)
+	})
+
+	t.Run("should close the consumer when EventProcessor never ran", func(t *testing.T) {
+		mockProducer, _ := kafka.NewMockCluster(1)
+		defer mockProducer.Close()
+
+		c := consumer.NewConsumer(mockProducer.BootstrapServers(), "group", "idle", false)
+		if err := c.Connect(); err != nil {
+			t.Fatal(ErrConnectFixture)
+		}
+
+		assert.NotPanics(t, c.Stop)
+		assert.NotPanics(t, c.Stop)
+	})
+}
+
+func TestConsumer_WithTelemetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should continue the producer trace through message headers", func(t *testing.T) {
+		mockProducer, _ := kafka.NewMockCluster(1)
+		defer mockProducer.Close()
+
+		broker := mockProducer.BootstrapServers()
+		recorder := telemetrytest.New()
+
+		c := consumer.NewConsumer(broker, "group", "traced", false, consumer.WithTelemetry(recorder.Config))
+		if err := c.Connect(); err != nil {
+			t.Fatal(ErrConnectFixture)
+		}
+
+		c.SetHandlers(map[string]func([]byte) error{
+			"traced event": func([]byte) error { return nil },
+		})
+
+		p, _ := producer.NewProducer(broker, 2000, producer.WithTelemetry(recorder.Config))
+		_ = p.Connect()
+		defer p.Close()
+
+		assert.NoError(t, <-p.Send("traced", []byte(`key`), []byte(`{"name":"traced event"}`)))
+
+		go c.EventProcessor()
+		defer c.Stop()
+
+		assert.Eventually(t, func() bool {
+			return len(recorder.Spans()) == 2
+		}, 10*time.Second, 50*time.Millisecond)
+
+		spans := recorder.Spans()
+		publish, process := spans[0], spans[1]
+
+		assert.Equal(t, "traced publish", publish.Name)
+		assert.Equal(t, "traced process", process.Name)
+		assert.Equal(t, publish.SpanContext.TraceID(), process.SpanContext.TraceID())
+		assert.Equal(t, publish.SpanContext.SpanID(), process.Parent.SpanID())
+	})
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestConsumer_WithLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should log handler failures with message coordinates and skip nil errors", func(t *testing.T) {
+		mockProducer, _ := kafka.NewMockCluster(1)
+		defer mockProducer.Close()
+
+		broker := mockProducer.BootstrapServers()
+
+		var buf syncBuffer
+
+		log := logger.NewSlog(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+		c := consumer.NewConsumer(broker, "group", "logged", false, consumer.WithLogger(log))
+		if err := c.Connect(); err != nil {
+			t.Fatal(ErrConnectFixture)
+		}
+
+		c.SetHandlers(map[string]func([]byte) error{
+			"ok event":      func([]byte) error { return nil },
+			"failing event": func([]byte) error { return errors.New("handler exploded") },
+		})
+
+		p, _ := producer.NewProducer(broker, 2000)
+		_ = p.Connect()
+		defer p.Close()
+
+		assert.NoError(t, <-p.Send("logged", []byte(`key`), []byte(`{"name":"ok event"}`)))
+		assert.NoError(t, <-p.Send("logged", []byte(`key`), []byte(`{"name":"failing event"}`)))
+
+		go c.EventProcessor()
+		defer c.Stop()
+
+		assert.Eventually(t, func() bool {
+			return strings.Contains(buf.String(), "handler exploded")
+		}, 10*time.Second, 50*time.Millisecond)
+
+		assert.Contains(t, buf.String(), `"level":"ERROR","msg":"kafka message [processing failed]","topic":"logged","partition":`)
+		assert.NotContains(t, buf.String(), "<nil>")
+		assert.NotContains(t, buf.String(), "ok event")
+	})
+}
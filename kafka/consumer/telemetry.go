@@ -0,0 +1,69 @@
+package consumer
+
+import (
+	"context"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/dot-backend/synergetic-craft/kafka/producer"
+	"github.com/dot-backend/synergetic-craft/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type consumerTelemetry struct {
+	tracer      trace.Tracer
+	propagator  propagation.TextMapPropagator
+	instruments telemetry.Instruments
+}
+
+func WithTelemetry(conf telemetry.Config) Option {
+	return func(c *consumer) {
+		c.telemetry = &consumerTelemetry{
+			tracer:      conf.Tracer("kafka/consumer"),
+			propagator:  conf.Propagation(),
+			instruments: telemetry.NewInstruments(conf.Meter("kafka/consumer"), "messaging.process"),
+		}
+	}
+}
+
+// start opens a process span parented to the producer's trace context carried
+// in the message headers.
+func (t *consumerTelemetry) start(msg *kafka.Message, groupID string) func(err error) {
+	if t == nil {
+		return func(error) {}
+	}
+
+	var topic string
+	if msg.TopicPartition.Topic != nil {
+		topic = *msg.TopicPartition.Topic
+	}
+
+	startedAt := time.Now()
+
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystem("kafka"),
+		semconv.MessagingDestinationName(topic),
+		semconv.MessagingOperationProcess,
+		semconv.MessagingKafkaConsumerGroup(groupID),
+	}
+
+	ctx := t.propagator.Extract(context.Background(), &producer.HeadersCarrier{Message: msg})
+
+	ctx, span := t.tracer.Start(ctx, topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(
+			semconv.MessagingKafkaDestinationPartition(int(msg.TopicPartition.Partition)),
+			semconv.MessagingKafkaMessageOffset(int(msg.TopicPartition.Offset)),
+			semconv.MessagingMessagePayloadSizeBytes(len(msg.Value)),
+		),
+	)
+
+	return func(err error) {
+		t.instruments.Record(ctx, startedAt, err, attrs...)
+		telemetry.End(span, err)
+	}
+}
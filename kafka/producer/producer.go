@@ -1,12 +1,13 @@
 package producer
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
-	"github.com/labstack/gommon/log"
+	"github.com/dot-backend/synergetic-craft/logger"
 )
 
 type Producer interface {
@@ -15,6 +16,13 @@ type Producer interface {
 	Send(topic string, key, message []byte) <-chan error
 }
 
+// ContextProducer sends messages carrying the trace from ctx in their headers.
+type ContextProducer interface {
+	SendContext(ctx context.Context, topic string, key, message []byte) <-chan error
+}
+
+type Option func(*producer)
+
 type BrokerConn struct {
 	broker         string
 	timeoutMessage time.Duration
@@ -23,22 +31,35 @@ type BrokerConn struct {
 type producer struct {
 	brokerConn BrokerConn
 	producer   *kafka.Producer
+	telemetry  *producerTelemetry
+	log        logger.Logger
 }
 
-func NewProducer(broker string, timeoutMessage int64) (Producer, error) {
+func NewProducer(broker string, timeoutMessage int64, opts ...Option) (Producer, error) {
 	if broker == "" || timeoutMessage == 0 {
 		return nil, errors.New("parameters cannot be zero")
 	}
 
-	return &producer{
+	p := &producer{
 		brokerConn: BrokerConn{
 			broker:         broker,
 			timeoutMessage: time.Duration(timeoutMessage) * time.Millisecond,
 		},
-	}, nil
+		log: logger.Nop(),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
 }
 
 func (k *producer) Send(topic string, key, message []byte) <-chan error {
+	return k.SendContext(context.Background(), topic, key, message)
+}
+
+func (k *producer) SendContext(ctx context.Context, topic string, key, message []byte) <-chan error {
 	responseChan := make(chan error)
 
 	msg := &kafka.Message{
@@ -47,11 +68,21 @@ func (k *producer) Send(topic string, key, message []byte) <-chan error {
 		Key:            key,
 	}
 
+	start := time.Now()
+	finish := k.telemetry.start(ctx, msg)
+
+	done := func(delivered *kafka.Message, err error) {
+		finish(delivered, err)
+		k.logDelivery(ctx, msg, delivered, time.Since(start), err)
+	}
+
 	go func() {
 		deliveryChan := make(chan kafka.Event)
 
 		err := k.producer.Produce(msg, deliveryChan)
 		if err != nil {
+			done(nil, err)
+
 			responseChan <- fmt.Errorf("error producing message: %v", err)
 			close(responseChan)
 
@@ -60,11 +91,15 @@ func (k *producer) Send(topic string, key, message []byte) <-chan error {
 
 		select {
 		case <-time.After(k.brokerConn.timeoutMessage):
-			responseChan <- errors.New("kafka message [time exceeded]")
+			err = errors.New("kafka message [time exceeded]")
+			done(nil, err)
+
+			responseChan <- err
 			close(responseChan)
 
 		case result := <-deliveryChan:
 			msgResponse := result.(*kafka.Message)
+			done(msgResponse, msgResponse.TopicPartition.Error)
 
 			if msgResponse.TopicPartition.Error != nil {
 				responseChan <- msgResponse.TopicPartition.Error
@@ -73,14 +108,32 @@ func (k *producer) Send(topic string, key, message []byte) <-chan error {
 			}
 
 			close(responseChan)
-
-			log.Infof("kafka message [topic = %s] [value = %s]", msgResponse.TopicPartition.Topic, msgResponse.Value)
 		}
 	}()
 
 	return responseChan
 }
 
+func WithLogger(log logger.Logger) Option {
+	return func(p *producer) {
+		p.log = log
+	}
+}
+
+func (k *producer) logDelivery(ctx context.Context, msg *kafka.Message, delivered *kafka.Message, latency time.Duration, err error) {
+	fields := []logger.Field{logger.Topic(*msg.TopicPartition.Topic), logger.Latency(latency)}
+	if delivered != nil {
+		fields = append(fields, logger.Partition(delivered.TopicPartition.Partition), logger.Offset(int64(delivered.TopicPartition.Offset)))
+	}
+
+	if err != nil {
+		k.log.Log(ctx, logger.LevelError, "kafka message [delivery failed]", append(fields, logger.Err(err))...)
+		return
+	}
+
+	k.log.Log(ctx, logger.LevelDebug, "kafka message [delivered]", append(fields, logger.Payload(msg.Value))...)
+}
+
 func (k *producer) Connect() error {
 	conn, err := kafka.NewProducer(&kafka.ConfigMap{
 		"bootstrap.servers": k.brokerConn.broker,
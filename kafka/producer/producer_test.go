@@ -1,10 +1,14 @@
 package producer_test
 
 import (
+	"bytes"
+	"log/slog"
+	"testing"
+
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	kafkaLocal "github.com/dot-backend/synergetic-craft/kafka/producer"
+	"github.com/dot-backend/synergetic-craft/logger"
 	"github.com/stretchr/testify/assert"
-	kafkaLocal "synergetic-craft/kafka/producer"
-	"testing"
 )
 
 const (
@@ -18,6 +22,7 @@ func TestNewProducer(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.NotNil(t, p)
+		assert.Implements(t, (*kafkaLocal.ContextProducer)(nil), p)
 	})
 
 	t.Run("should return error when parameters are not send", func(t *testing.T) {
@@ -97,3 +102,30 @@ func TestProducer_Send(t *testing.T) {
 		assert.Equal(t, "error producing message: Local: Invalid argument or configuration", err.Error())
 	})
 }
+
+func TestProducer_WithLogger(t *testing.T) {
+	t.Run("should log deliveries at debug with the payload redacted", func(t *testing.T) {
+		mockProducer, _ := kafka.NewMockCluster(1)
+		defer mockProducer.Close()
+
+		var buf bytes.Buffer
+
+		log := logger.WithRedaction(
+			logger.NewSlog(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+			logger.RedactPayload,
+		)
+
+		p, _ := kafkaLocal.NewProducer(mockProducer.BootstrapServers(), 2000, kafkaLocal.WithLogger(log))
+		if err := p.Connect(); err != nil {
+			t.Fatal(ErrConnectFixture)
+		}
+		defer p.Close()
+
+		err := <-p.Send("test", []byte(`key`), []byte(`{"name":"secret event"}`))
+
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), `"level":"DEBUG","msg":"kafka message [delivered]","topic":"test"`)
+		assert.Contains(t, buf.String(), `"offset":`)
+		assert.NotContains(t, buf.String(), "secret event")
+	})
+}
@@ -0,0 +1,104 @@
+package producer
+
+import (
+	"context"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/dot-backend/synergetic-craft/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type producerTelemetry struct {
+	tracer      trace.Tracer
+	propagator  propagation.TextMapPropagator
+	instruments telemetry.Instruments
+}
+
+func WithTelemetry(conf telemetry.Config) Option {
+	return func(p *producer) {
+		p.telemetry = &producerTelemetry{
+			tracer:      conf.Tracer("kafka/producer"),
+			propagator:  conf.Propagation(),
+			instruments: telemetry.NewInstruments(conf.Meter("kafka/producer"), "messaging.publish"),
+		}
+	}
+}
+
+// start opens a publish span, injects its context into the message headers and
+// returns the callback that closes it once the delivery report arrives.
+func (t *producerTelemetry) start(ctx context.Context, msg *kafka.Message) func(delivered *kafka.Message, err error) {
+	if t == nil {
+		return func(*kafka.Message, error) {}
+	}
+
+	topic := *msg.TopicPartition.Topic
+	startedAt := time.Now()
+
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystem("kafka"),
+		semconv.MessagingDestinationName(topic),
+		semconv.MessagingOperationPublish,
+	}
+
+	ctx, span := t.tracer.Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(semconv.MessagingMessagePayloadSizeBytes(len(msg.Value))),
+	)
+
+	if len(msg.Key) > 0 {
+		span.SetAttributes(semconv.MessagingKafkaMessageKey(string(msg.Key)))
+	}
+
+	t.propagator.Inject(ctx, &HeadersCarrier{Message: msg})
+
+	return func(delivered *kafka.Message, err error) {
+		if delivered != nil {
+			span.SetAttributes(
+				semconv.MessagingKafkaDestinationPartition(int(delivered.TopicPartition.Partition)),
+				semconv.MessagingKafkaMessageOffset(int(delivered.TopicPartition.Offset)),
+			)
+		}
+
+		t.instruments.Record(ctx, startedAt, err, attrs...)
+		telemetry.End(span, err)
+	}
+}
+
+type HeadersCarrier struct {
+	Message *kafka.Message
+}
+
+func (c *HeadersCarrier) Get(key string) string {
+	for _, header := range c.Message.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+func (c *HeadersCarrier) Set(key, value string) {
+	for i, header := range c.Message.Headers {
+		if header.Key == key {
+			c.Message.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+
+	c.Message.Headers = append(c.Message.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c *HeadersCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.Message.Headers))
+	for _, header := range c.Message.Headers {
+		keys = append(keys, header.Key)
+	}
+
+	return keys
+}
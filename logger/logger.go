@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+const (
+	KeyTopic     = "topic"
+	KeyPartition = "partition"
+	KeyOffset    = "offset"
+	KeyStatus    = "status"
+	KeyLatency   = "latency"
+	KeyError     = "error"
+	KeyPayload   = "payload"
+)
+
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Topic(topic string) Field {
+	return Field{Key: KeyTopic, Value: topic}
+}
+
+func Partition(partition int32) Field {
+	return Field{Key: KeyPartition, Value: partition}
+}
+
+func Offset(offset int64) Field {
+	return Field{Key: KeyOffset, Value: offset}
+}
+
+func Status(code int) Field {
+	return Field{Key: KeyStatus, Value: code}
+}
+
+func Latency(latency time.Duration) Field {
+	return Field{Key: KeyLatency, Value: latency}
+}
+
+func Err(err error) Field {
+	return Field{Key: KeyError, Value: err}
+}
+
+func Payload(payload []byte) Field {
+	return Field{Key: KeyPayload, Value: payload}
+}
+
+type Logger interface {
+	Enabled(ctx context.Context, level Level) bool
+	Log(ctx context.Context, level Level, msg string, fields ...Field)
+}
+
+type nop struct{}
+
+func Nop() Logger {
+	return nop{}
+}
+
+func (nop) Enabled(context.Context, Level) bool { return false }
+
+func (nop) Log(context.Context, Level, string, ...Field) {}
+
+type Redactor func(field Field) Field
+
+type redacting struct {
+	next      Logger
+	redactors []Redactor
+}
+
+// WithRedaction runs every field through the redactors before it reaches
+// next, so payloads and secrets can be masked in one place for all clients.
+func WithRedaction(next Logger, redactors ...Redactor) Logger {
+	return &redacting{
+		next:      next,
+		redactors: redactors,
+	}
+}
+
+func (r *redacting) Enabled(ctx context.Context, level Level) bool {
+	return r.next.Enabled(ctx, level)
+}
+
+func (r *redacting) Log(ctx context.Context, level Level, msg string, fields ...Field) {
+	redacted := make([]Field, len(fields))
+
+	for i, field := range fields {
+		for _, redact := range r.redactors {
+			field = redact(field)
+		}
+
+		redacted[i] = field
+	}
+
+	r.next.Log(ctx, level, msg, redacted...)
+}
+
+func RedactPayload(field Field) Field {
+	if field.Key != KeyPayload {
+		return field
+	}
+
+	if payload, ok := field.Value.([]byte); ok {
+		return Field{Key: field.Key, Value: fmt.Sprintf("[redacted %d bytes]", len(payload))}
+	}
+
+	return Field{Key: field.Key, Value: "[redacted]"}
+}
+
+func RedactKeys(keys ...string) Redactor {
+	return func(field Field) Field {
+		for _, key := range keys {
+			if field.Key == key {
+				return Field{Key: field.Key, Value: "[redacted]"}
+			}
+		}
+
+		return field
+	}
+}
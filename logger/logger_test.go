@@ -0,0 +1,74 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func newJSONLogger(buf *bytes.Buffer, level slog.Level) logger.Logger {
+	return logger.NewSlog(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: level})))
+}
+
+func TestSlogLogger(t *testing.T) {
+	t.Run("should write structured fields through slog", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		log := newJSONLogger(&buf, slog.LevelDebug)
+
+		log.Log(context.TODO(), logger.LevelError, "kafka message [delivery failed]",
+			logger.Topic("orders"),
+			logger.Partition(2),
+			logger.Offset(42),
+			logger.Latency(1500*time.Millisecond),
+			logger.Err(errors.New("broker down")),
+		)
+
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+		assert.Equal(t, "ERROR", entry["level"])
+		assert.Equal(t, "kafka message [delivery failed]", entry["msg"])
+		assert.Equal(t, "orders", entry["topic"])
+		assert.Equal(t, float64(2), entry["partition"])
+		assert.Equal(t, float64(42), entry["offset"])
+		assert.Equal(t, float64(1500*time.Millisecond), entry["latency"])
+		assert.Equal(t, "broker down", entry["error"])
+	})
+
+	t.Run("should skip entries below the handler level", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		log := newJSONLogger(&buf, slog.LevelInfo)
+		log.Log(context.TODO(), logger.LevelDebug, "noisy")
+
+		assert.False(t, log.Enabled(context.TODO(), logger.LevelDebug))
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestWithRedaction(t *testing.T) {
+	t.Run("should mask payloads and configured keys", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		log := logger.WithRedaction(newJSONLogger(&buf, slog.LevelDebug), logger.RedactPayload, logger.RedactKeys("authorization"))
+
+		log.Log(context.TODO(), logger.LevelInfo, "sent",
+			logger.Payload([]byte(`{"card":"4111"}`)),
+			logger.Any("authorization", "Bearer secret"),
+			logger.Topic("payments"),
+		)
+
+		assert.NotContains(t, buf.String(), "4111")
+		assert.NotContains(t, buf.String(), "secret")
+		assert.Contains(t, buf.String(), `"payload":"[redacted 15 bytes]"`)
+		assert.Contains(t, buf.String(), `"topic":"payments"`)
+	})
+}
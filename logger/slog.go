@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func NewSlog(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &slogLogger{logger: logger}
+}
+
+func (s *slogLogger) Enabled(ctx context.Context, level Level) bool {
+	return s.logger.Enabled(ctx, slog.Level(level))
+}
+
+func (s *slogLogger) Log(ctx context.Context, level Level, msg string, fields ...Field) {
+	if !s.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		attrs = append(attrs, fieldAttr(field))
+	}
+
+	s.logger.LogAttrs(ctx, slog.Level(level), msg, attrs...)
+}
+
+func fieldAttr(field Field) slog.Attr {
+	switch value := field.Value.(type) {
+	case error:
+		return slog.String(field.Key, value.Error())
+	case []byte:
+		return slog.String(field.Key, string(value))
+	}
+
+	return slog.Any(field.Key, field.Value)
+}
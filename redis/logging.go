@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/logger"
+	rds "github.com/redis/go-redis/v9"
+)
+
+func WithLogger(log logger.Logger) Option {
+	return func(client *rds.Client) {
+		client.AddHook(&loggingHook{log: log})
+	}
+}
+
+type loggingHook struct {
+	log logger.Logger
+}
+
+func (h *loggingHook) DialHook(next rds.DialHook) rds.DialHook {
+	return next
+}
+
+func (h *loggingHook) ProcessHook(next rds.ProcessHook) rds.ProcessHook {
+	return func(ctx context.Context, cmd rds.Cmder) error {
+		start := time.Now()
+
+		err := next(ctx, cmd)
+		h.logCommand(ctx, strings.ToUpper(cmd.Name()), time.Since(start), err)
+
+		return err
+	}
+}
+
+func (h *loggingHook) ProcessPipelineHook(next rds.ProcessPipelineHook) rds.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []rds.Cmder) error {
+		start := time.Now()
+
+		err := next(ctx, cmds)
+		h.logCommand(ctx, "PIPELINE", time.Since(start), err)
+
+		return err
+	}
+}
+
+func (h *loggingHook) logCommand(ctx context.Context, command string, latency time.Duration, err error) {
+	fields := []logger.Field{logger.Any("command", command), logger.Latency(latency)}
+
+	if err != nil && !errors.Is(err, ErrNil) {
+		h.log.Log(ctx, logger.LevelError, "redis command [failed]", append(fields, logger.Err(err))...)
+		return
+	}
+
+	h.log.Log(ctx, logger.LevelDebug, "redis command [done]", fields...)
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 )
 
+var ErrNil = rds.Nil
+
 type ClientRedis interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
@@ -14,6 +16,11 @@ type ClientRedis interface {
 	MGet(ctx context.Context, keys ...string) (map[string]string, error)
 }
 
+// Evaler runs Lua scripts on the server.
+type Evaler interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
 type Config struct {
 	Address       string
 	Password      string
@@ -22,20 +29,22 @@ type Config struct {
 	DB            int
 }
 
+type Option func(*rds.Client)
+
 type redis struct {
 	client rds.Cmdable
 }
 
-func NewClient(conf Config) ClientRedis {
+func NewClient(conf Config, opts ...Option) ClientRedis {
 	return &redis{
-		client: initClient(conf),
+		client: initClient(conf, opts...),
 	}
 }
 
-func initClient(conf Config) *rds.Client {
+func initClient(conf Config, opts ...Option) *rds.Client {
 	timeout := time.Duration(conf.TimeoutMillis) * time.Millisecond
 
-	opts := &rds.Options{
+	options := &rds.Options{
 		Addr:         conf.Address,
 		Password:     conf.Password,
 		PoolSize:     conf.PoolSize,
@@ -46,7 +55,10 @@ func initClient(conf Config) *rds.Client {
 		DB:           conf.DB,
 	}
 
-	client := rds.NewClient(opts)
+	client := rds.NewClient(options)
+	for _, opt := range opts {
+		opt(client)
+	}
 
 	_, err := client.Ping(context.Background()).Result()
 	if err != nil {
@@ -109,3 +121,7 @@ func (r *redis) MGet(ctx context.Context, keys ...string) (map[string]string, er
 
 	return resultMap, nil
 }
+
+func (r *redis) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return r.client.Eval(ctx, script, keys, args...).Result()
+}
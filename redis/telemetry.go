@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/dot-backend/synergetic-craft/telemetry"
+	rds "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func WithTelemetry(conf telemetry.Config) Option {
+	return func(client *rds.Client) {
+		client.AddHook(newTelemetryHook(conf, client.Options().Addr))
+
+		telemetry.RegisterPoolGauges(conf.Meter("redis"), client.Options().Addr, func() telemetry.PoolStats {
+			stats := client.PoolStats()
+
+			return telemetry.PoolStats{
+				Used: int64(stats.TotalConns) - int64(stats.IdleConns),
+				Idle: int64(stats.IdleConns),
+				Max:  int64(client.Options().PoolSize),
+			}
+		})
+	}
+}
+
+type telemetryHook struct {
+	tracer      trace.Tracer
+	instruments telemetry.Instruments
+	attrs       []attribute.KeyValue
+}
+
+func newTelemetryHook(conf telemetry.Config, addr string) *telemetryHook {
+	attrs := []attribute.KeyValue{semconv.DBSystemRedis}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		attrs = append(attrs, semconv.ServerAddress(host))
+	}
+
+	return &telemetryHook{
+		tracer:      conf.Tracer("redis"),
+		instruments: telemetry.NewInstruments(conf.Meter("redis"), "db.client.operation"),
+		attrs:       attrs,
+	}
+}
+
+func (h *telemetryHook) DialHook(next rds.DialHook) rds.DialHook {
+	return next
+}
+
+func (h *telemetryHook) ProcessHook(next rds.ProcessHook) rds.ProcessHook {
+	return func(ctx context.Context, cmd rds.Cmder) error {
+		return h.trace(ctx, strings.ToUpper(cmd.Name()), func(ctx context.Context) error {
+			return next(ctx, cmd)
+		})
+	}
+}
+
+func (h *telemetryHook) ProcessPipelineHook(next rds.ProcessPipelineHook) rds.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []rds.Cmder) error {
+		return h.trace(ctx, "PIPELINE", func(ctx context.Context) error {
+			return next(ctx, cmds)
+		})
+	}
+}
+
+func (h *telemetryHook) trace(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	attrs := append(h.attrs[:len(h.attrs):len(h.attrs)], semconv.DBOperation(operation))
+
+	ctx, span := h.tracer.Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+	err := fn(ctx)
+
+	failure := err
+	if errors.Is(err, ErrNil) {
+		failure = nil
+	}
+
+	h.instruments.Record(ctx, start, failure, attrs...)
+	telemetry.End(span, failure)
+
+	return err
+}
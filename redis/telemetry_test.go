@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dot-backend/synergetic-craft/telemetry/telemetrytest"
+	rds "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestTelemetryHook(t *testing.T) {
+	t.Run("should trace commands and ignore cache misses as errors", func(t *testing.T) {
+		recorder := telemetrytest.New()
+		hook := newTelemetryHook(recorder.Config, "localhost:6379")
+
+		process := hook.ProcessHook(func(ctx context.Context, cmd rds.Cmder) error {
+			return ErrNil
+		})
+
+		err := process(context.Background(), rds.NewStringCmd(context.Background(), "get", "missing"))
+
+		assert.ErrorIs(t, err, ErrNil)
+
+		spans := recorder.Spans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "GET", spans[0].Name)
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+
+		errs, ok := recorder.Metric("db.client.operation.errors")
+		assert.False(t, ok, "unexpected error metric %v", errs)
+
+		duration, ok := recorder.Metric("db.client.operation.duration")
+		assert.True(t, ok)
+		assert.Equal(t, uint64(1), duration.Data.(metricdata.Histogram[float64]).DataPoints[0].Count)
+	})
+
+	t.Run("should expose pool gauges", func(t *testing.T) {
+		recorder := telemetrytest.New()
+		client := rds.NewClient(&rds.Options{Addr: "localhost:6379", PoolSize: 7})
+		defer client.Close()
+
+		WithTelemetry(recorder.Config)(client)
+
+		max, ok := recorder.Metric("db.client.connections.max")
+
+		assert.True(t, ok)
+		assert.Equal(t, int64(7), max.Data.(metricdata.Gauge[int64]).DataPoints[0].Value)
+	})
+}
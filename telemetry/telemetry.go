@@ -0,0 +1,137 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationPrefix = "github.com/dot-backend/synergetic-craft/"
+
+type Config struct {
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+	Propagator     propagation.TextMapPropagator
+}
+
+func (c Config) Tracer(pkg string) trace.Tracer {
+	provider := c.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return provider.Tracer(instrumentationPrefix+pkg, trace.WithSchemaURL(semconv.SchemaURL))
+}
+
+func (c Config) Meter(pkg string) metric.Meter {
+	provider := c.MeterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	return provider.Meter(instrumentationPrefix+pkg, metric.WithSchemaURL(semconv.SchemaURL))
+}
+
+// Propagation defaults to W3C trace context and baggage instead of the global
+// propagator, which is a no-op until the application configures one.
+func (c Config) Propagation() propagation.TextMapPropagator {
+	if c.Propagator == nil {
+		return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+
+	return c.Propagator
+}
+
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// DurationBuckets are second-based boundaries for client latency histograms.
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+type Instruments struct {
+	Duration metric.Float64Histogram
+	Errors   metric.Int64Counter
+}
+
+// NewInstruments creates the latency histogram and error counter shared by
+// every client. Failures are reported to the global otel error handler and
+// leave a no-op instrument in place, as instrumentation must never break calls.
+func NewInstruments(meter metric.Meter, prefix string) Instruments {
+	duration, err := meter.Float64Histogram(prefix+".duration",
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(DurationBuckets...),
+	)
+	if err != nil {
+		otel.Handle(err)
+		duration, _ = noop.NewMeterProvider().Meter("").Float64Histogram(prefix + ".duration")
+	}
+
+	errs, err := meter.Int64Counter(prefix+".errors", metric.WithUnit("{error}"))
+	if err != nil {
+		otel.Handle(err)
+		errs, _ = noop.NewMeterProvider().Meter("").Int64Counter(prefix + ".errors")
+	}
+
+	return Instruments{Duration: duration, Errors: errs}
+}
+
+func (i Instruments) Record(ctx context.Context, start time.Time, err error, attrs ...attribute.KeyValue) {
+	opt := metric.WithAttributes(attrs...)
+
+	i.Duration.Record(ctx, time.Since(start).Seconds(), opt)
+
+	if err != nil {
+		i.Errors.Add(ctx, 1, opt)
+	}
+}
+
+type PoolStats struct {
+	Used int64
+	Idle int64
+	Max  int64
+}
+
+// RegisterPoolGauges exposes connection pool usage following the db client
+// connection metric conventions; stats is polled on every collection.
+func RegisterPoolGauges(meter metric.Meter, pool string, stats func() PoolStats) {
+	usage, err := meter.Int64ObservableGauge("db.client.connections.usage", metric.WithUnit("{connection}"))
+	if err != nil {
+		otel.Handle(err)
+		return
+	}
+
+	max, err := meter.Int64ObservableGauge("db.client.connections.max", metric.WithUnit("{connection}"))
+	if err != nil {
+		otel.Handle(err)
+		return
+	}
+
+	poolName := attribute.String("pool.name", pool)
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		current := stats()
+
+		o.ObserveInt64(usage, current.Used, metric.WithAttributes(poolName, attribute.String("state", "used")))
+		o.ObserveInt64(usage, current.Idle, metric.WithAttributes(poolName, attribute.String("state", "idle")))
+		o.ObserveInt64(max, current.Max, metric.WithAttributes(poolName))
+
+		return nil
+	}, usage, max)
+	if err != nil {
+		otel.Handle(err)
+	}
+}
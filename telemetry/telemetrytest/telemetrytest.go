@@ -0,0 +1,55 @@
+package telemetrytest
+
+import (
+	"context"
+
+	"github.com/dot-backend/synergetic-craft/telemetry"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Recorder wires in-memory trace and metric exporters into a telemetry.Config
+// so tests can assert on what a client emitted.
+type Recorder struct {
+	Config telemetry.Config
+
+	exporter *tracetest.InMemoryExporter
+	reader   *sdkmetric.ManualReader
+}
+
+func New() *Recorder {
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+
+	return &Recorder{
+		Config: telemetry.Config{
+			TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+			MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		},
+		exporter: exporter,
+		reader:   reader,
+	}
+}
+
+func (r *Recorder) Spans() tracetest.SpanStubs {
+	return r.exporter.GetSpans()
+}
+
+func (r *Recorder) Metric(name string) (metricdata.Metrics, bool) {
+	var data metricdata.ResourceMetrics
+	if err := r.reader.Collect(context.Background(), &data); err != nil {
+		return metricdata.Metrics{}, false
+	}
+
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+
+	return metricdata.Metrics{}, false
+}